@@ -0,0 +1,49 @@
+package call
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "call"
+	short = "Call the venturemark api ad hoc."
+	long  = `Call the venturemark api ad hoc and print the response as JSON. The first
+argument is the service, the second argument the action. All further arguments
+are metadata of the form key=val. Requests are authenticated as the identity
+given by --as.
+
+    cfm call timeline search venture.venturemark.co/id=123 --as one@user.com
+
+    cfm call venture create --property name=IBM
+
+    cfm call timeline update venture.venturemark.co/id=123 timeline.venturemark.co/id=456 \
+        --patch replace:/obj/property/stat:archived
+
+Services are invite, message, role, texupd, timeline, update, user and venture.
+Actions are create, delete, search and update.
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name + " <service> <action> [key=val ...]",
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,23 @@
+package call
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var invalidArgumentError = &tracer.Error{
+	Kind: "invalidArgumentError",
+}
+
+func IsInvalidArgument(err error) bool {
+	return errors.Is(err, invalidArgumentError)
+}
+
+var invalidFlagError = &tracer.Error{
+	Kind: "invalidFlagError",
+}
+
+func IsInvalidFlag(err error) bool {
+	return errors.Is(err, invalidFlagError)
+}
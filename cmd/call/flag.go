@@ -0,0 +1,47 @@
+package call
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	Address  string
+	As       string
+	Patch    []string
+	Property []string
+	Request  string
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Address, "address", "a", "127.0.0.1:7777", "The address of the apiserver.")
+	cmd.Flags().StringVarP(&f.As, "as", "", "one@user.com", "The mail address of the identity the request is authenticated as.")
+	cmd.Flags().StringArrayVarP(&f.Patch, "patch", "p", nil, "JSON patch operations of the form ope:pat[:val], e.g. replace:/obj/property/name:IBM.")
+	cmd.Flags().StringArrayVarP(&f.Property, "property", "", nil, "Properties of the form key=val, e.g. name=IBM.")
+	cmd.Flags().StringVarP(&f.Request, "request", "r", "", "The raw JSON request, overriding metadata, patches and properties.")
+}
+
+func (f *flag) Validate() error {
+	if f.Address == "" {
+		return tracer.Maskf(invalidFlagError, "-a/--address must not be empty")
+	}
+	if f.As == "" {
+		return tracer.Maskf(invalidFlagError, "--as must not be empty")
+	}
+
+	for _, p := range f.Patch {
+		if len(strings.SplitN(p, ":", 3)) < 2 {
+			return tracer.Maskf(invalidFlagError, "-p/--patch must be of the form ope:pat[:val]")
+		}
+	}
+
+	for _, p := range f.Property {
+		if !strings.Contains(p, "=") {
+			return tracer.Maskf(invalidFlagError, "--property must be of the form key=val")
+		}
+	}
+
+	return nil
+}
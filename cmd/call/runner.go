@@ -0,0 +1,197 @@
+package call
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/compat"
+	"github.com/venturemark/cfm/pkg/oauth"
+)
+
+var actions = map[string]string{
+	"create": "Create",
+	"delete": "Delete",
+	"search": "Search",
+	"update": "Update",
+}
+
+var services = map[string]bool{
+	"invite":   true,
+	"message":  true,
+	"role":     true,
+	"texupd":   true,
+	"timeline": true,
+	"update":   true,
+	"user":     true,
+	"venture":  true,
+}
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	var met string
+	{
+		met, err = method(args)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var req string
+	{
+		req, err = r.request(args[2:])
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var cli *client.Client
+	{
+		c := client.Config{
+			Address:     r.flag.Address,
+			Credentials: oauth.NewInsecure(r.flag.As),
+		}
+
+		cli, err = client.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		defer cli.Close()
+	}
+
+	var res string
+	{
+		res, err = compat.Current().Call(ctx, cli.Grpc(), met, req)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		b := &bytes.Buffer{}
+
+		err = json.Indent(b, []byte(res), "", "  ")
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), b.String())
+	}
+
+	return nil
+}
+
+// request returns the JSON request built from the given metadata arguments
+// and the patch and property flags. Requests without any input are empty.
+func (r *runner) request(args []string) (string, error) {
+	if r.flag.Request != "" {
+		return r.flag.Request, nil
+	}
+
+	obj := map[string]interface{}{}
+
+	if len(args) != 0 {
+		met := map[string]string{}
+
+		for _, a := range args {
+			spl := strings.SplitN(a, "=", 2)
+			if len(spl) != 2 {
+				return "", tracer.Maskf(invalidArgumentError, "metadata %q must be of the form key=val", a)
+			}
+
+			met[spl[0]] = spl[1]
+		}
+
+		obj["metadata"] = met
+	}
+
+	if len(r.flag.Property) != 0 {
+		pro := map[string]string{}
+
+		for _, p := range r.flag.Property {
+			spl := strings.SplitN(p, "=", 2)
+			pro[spl[0]] = spl[1]
+		}
+
+		obj["property"] = pro
+	}
+
+	if len(r.flag.Patch) != 0 {
+		var pat []map[string]string
+
+		for _, p := range r.flag.Patch {
+			spl := strings.SplitN(p, ":", 3)
+
+			o := map[string]string{
+				"ope": spl[0],
+				"pat": spl[1],
+			}
+
+			if len(spl) == 3 {
+				o["val"] = spl[2]
+			}
+
+			pat = append(pat, o)
+		}
+
+		obj["jsnpatch"] = pat
+	}
+
+	if len(obj) == 0 {
+		return "{}", nil
+	}
+
+	byt, err := json.Marshal(map[string]interface{}{"obj": []interface{}{obj}})
+	if err != nil {
+		return "", tracer.Mask(err)
+	}
+
+	return string(byt), nil
+}
+
+// method returns the full gRPC method name, e.g. venture.API/Search, for the
+// service and action given as the first two arguments.
+func method(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", tracer.Maskf(invalidArgumentError, "service and action must be given")
+	}
+
+	if !services[args[0]] {
+		return "", tracer.Maskf(invalidArgumentError, "service %q must be known", args[0])
+	}
+
+	act, ok := actions[args[1]]
+	if !ok {
+		return "", tracer.Maskf(invalidArgumentError, "action %q must be known", args[1])
+	}
+
+	return fmt.Sprintf("%s.API/%s", args[0], act), nil
+}
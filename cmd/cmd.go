@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/cmd/call"
+	"github.com/venturemark/cfm/cmd/export"
+	"github.com/venturemark/cfm/cmd/imp"
+	"github.com/venturemark/cfm/cmd/migrate"
+	"github.com/venturemark/cfm/cmd/run"
+	"github.com/venturemark/cfm/cmd/schema"
+	"github.com/venturemark/cfm/cmd/seed"
+)
+
+const (
+	name  = "cfm"
+	short = "Conformance tooling for the venturemark api."
+	long  = "Conformance tooling for the venturemark api."
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var err error
+
+	var cmdCall *cobra.Command
+	{
+		c := call.Config{}
+
+		cmdCall, err = call.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdExport *cobra.Command
+	{
+		c := export.Config{}
+
+		cmdExport, err = export.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdImport *cobra.Command
+	{
+		c := imp.Config{}
+
+		cmdImport, err = imp.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdMigrate *cobra.Command
+	{
+		c := migrate.Config{}
+
+		cmdMigrate, err = migrate.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdRun *cobra.Command
+	{
+		c := run.Config{}
+
+		cmdRun, err = run.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdSchema *cobra.Command
+	{
+		c := schema.Config{}
+
+		cmdSchema, err = schema.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdSeed *cobra.Command
+	{
+		c := seed.Config{}
+
+		cmdSeed, err = seed.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var c *cobra.Command
+	{
+		r := &runner{}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+			// We slience errors because we do not want to see spf13/cobra printing.
+			// The errors returned by the commands will be propagated to the main.go
+			// anyway, where we have custom error printing for the tool.
+			SilenceErrors: true,
+			// We slience usage because we do not want to see spf13/cobra printing
+			// usage when an error occurred. Usage is printed even for runtime
+			// errors.
+			SilenceUsage: true,
+		}
+
+		c.SetHelpCommand(&cobra.Command{Hidden: true})
+
+		c.AddCommand(cmdCall)
+		c.AddCommand(cmdExport)
+		c.AddCommand(cmdImport)
+		c.AddCommand(cmdMigrate)
+		c.AddCommand(cmdRun)
+		c.AddCommand(cmdSchema)
+		c.AddCommand(cmdSeed)
+	}
+
+	return c, nil
+}
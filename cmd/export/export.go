@@ -0,0 +1,42 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "export"
+	short = "Export a venture as versioned JSON archive."
+	long  = `Export a venture as versioned JSON archive. The venture is walked using the
+Search APIs, including its timelines, updates, messages, roles and invites.
+The venture must be visible to the identity given by --as. The archive can be
+imported using "cfm import", e.g. in order to clone production-like data into
+a local stack.
+
+    cfm export --venture 1613843069 --as one@user.com --out venture.json
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,34 @@
+package export
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	Address string
+	As      string
+	Out     string
+	Venture string
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Address, "address", "a", "127.0.0.1:7777", "The address of the apiserver.")
+	cmd.Flags().StringVarP(&f.As, "as", "", "one@user.com", "The mail address of the identity the venture is exported as.")
+	cmd.Flags().StringVarP(&f.Out, "out", "o", "", "The file the archive is written to, defaults to stdout.")
+	cmd.Flags().StringVarP(&f.Venture, "venture", "v", "", "The ID of the venture to export.")
+}
+
+func (f *flag) Validate() error {
+	if f.Address == "" {
+		return tracer.Maskf(invalidFlagError, "-a/--address must not be empty")
+	}
+	if f.As == "" {
+		return tracer.Maskf(invalidFlagError, "--as must not be empty")
+	}
+	if f.Venture == "" {
+		return tracer.Maskf(invalidFlagError, "-v/--venture must not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/archive"
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/oauth"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	var cli *client.Client
+	{
+		c := client.Config{
+			Address:     r.flag.Address,
+			Credentials: oauth.NewInsecure(r.flag.As),
+		}
+
+		cli, err = client.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		defer cli.Close()
+	}
+
+	var arc *archive.Archiver
+	{
+		c := archive.Config{
+			Client: cli,
+		}
+
+		arc, err = archive.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var byt []byte
+	{
+		a, err := arc.Export(ctx, r.flag.Venture)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		byt, err = json.MarshalIndent(a, "", "  ")
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if r.flag.Out == "" {
+		fmt.Fprintln(cmd.OutOrStdout(), string(byt))
+	} else {
+		err = os.WriteFile(r.flag.Out, byt, 0600)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "wrote venture %s to %s\n", r.flag.Venture, r.flag.Out)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package imp
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	Address string
+	As      string
+	In      string
+	Members bool
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Address, "address", "a", "127.0.0.1:7777", "The address of the apiserver.")
+	cmd.Flags().StringVarP(&f.As, "as", "", "one@user.com", "The mail address of the identity owning the imported venture.")
+	cmd.Flags().StringVarP(&f.In, "in", "i", "", "The file the archive is read from.")
+	cmd.Flags().BoolVarP(&f.Members, "members", "m", false, "Whether to recreate roles of other subjects using their original IDs.")
+}
+
+func (f *flag) Validate() error {
+	if f.Address == "" {
+		return tracer.Maskf(invalidFlagError, "-a/--address must not be empty")
+	}
+	if f.As == "" {
+		return tracer.Maskf(invalidFlagError, "--as must not be empty")
+	}
+	if f.In == "" {
+		return tracer.Maskf(invalidFlagError, "-i/--in must not be empty")
+	}
+
+	return nil
+}
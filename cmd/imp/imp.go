@@ -0,0 +1,43 @@
+package imp
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "import"
+	short = "Import a venture from a JSON archive."
+	long  = `Import a venture from a JSON archive written by "cfm export". The venture is
+recreated using the Create APIs under the identity given by --as as new owner,
+including its timelines, updates, messages, roles and invites. All IDs are
+remapped. Roles of subjects other than the exporting user are only recreated
+with --members, which is only sensible within the environment the venture got
+exported from.
+
+    cfm import --in venture.json --as two@user.com
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
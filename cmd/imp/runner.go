@@ -0,0 +1,94 @@
+package imp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/archive"
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/oauth"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	// Decoding into an allocated archive keeps it valid if the file contains
+	// null, in which case the version check of the import fails.
+	a := &archive.Archive{}
+	{
+		byt, err := os.ReadFile(r.flag.In)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		err = json.Unmarshal(byt, a)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var cli *client.Client
+	{
+		c := client.Config{
+			Address:     r.flag.Address,
+			Credentials: oauth.NewInsecure(r.flag.As),
+		}
+
+		cli, err = client.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		defer cli.Close()
+	}
+
+	var arc *archive.Archiver
+	{
+		c := archive.Config{
+			Client:  cli,
+			Members: r.flag.Members,
+		}
+
+		arc, err = archive.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var ids map[string]string
+	{
+		ids, err = arc.Import(ctx, a)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "imported venture %s as %s\n", a.Venture.ID, ids[a.Venture.ID])
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package migrate
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/cmd/migrate/seed"
+	"github.com/venturemark/cfm/cmd/migrate/verify"
+)
+
+const (
+	name  = "migrate"
+	short = "Verify that data survives upgrades of the apiserver."
+	long  = `Verify that data survives upgrades of the apiserver, e.g. when the Redis
+layout changes. Data is seeded against the old version and recorded in a state
+file. Once the new version got deployed against the same Redis, every seeded
+object is re-read and verified.
+
+    cfm migrate seed --out state.json
+    cfm migrate verify --in state.json
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var err error
+
+	var cmdSeed *cobra.Command
+	{
+		c := seed.Config{}
+
+		cmdSeed, err = seed.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdVerify *cobra.Command
+	{
+		c := verify.Config{}
+
+		cmdVerify, err = verify.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var c *cobra.Command
+	{
+		r := &runner{}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		c.AddCommand(cmdSeed)
+		c.AddCommand(cmdVerify)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,41 @@
+package seed
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	Address   string
+	Out       string
+	Seed      int64
+	Timelines int
+	Users     int
+	Ventures  int
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Address, "address", "a", "127.0.0.1:7777", "The address of the apiserver.")
+	cmd.Flags().StringVarP(&f.Out, "out", "o", "", "The file the state is written to.")
+	cmd.Flags().Int64VarP(&f.Seed, "seed", "s", 1, "The seed the content is generated from.")
+	cmd.Flags().IntVarP(&f.Timelines, "timelines", "", 2, "The number of timelines created per venture.")
+	cmd.Flags().IntVarP(&f.Users, "users", "u", 3, "The number of users created.")
+	cmd.Flags().IntVarP(&f.Ventures, "ventures", "", 2, "The number of ventures created per user.")
+}
+
+func (f *flag) Validate() error {
+	if f.Address == "" {
+		return tracer.Maskf(invalidFlagError, "-a/--address must not be empty")
+	}
+	if f.Out == "" {
+		return tracer.Maskf(invalidFlagError, "-o/--out must not be empty")
+	}
+	if f.Users < 2 {
+		return tracer.Maskf(invalidFlagError, "-u/--users must be at least 2")
+	}
+	if f.Timelines < 0 || f.Ventures < 0 {
+		return tracer.Maskf(invalidFlagError, "counts must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/migrate"
+	"github.com/venturemark/cfm/pkg/seed"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	var mig *migrate.Migrate
+	{
+		c := migrate.Config{
+			Address: r.flag.Address,
+			Seed: seed.Config{
+				Invites:   1,
+				Messages:  2,
+				Roles:     1,
+				Seed:      r.flag.Seed,
+				Timelines: r.flag.Timelines,
+				Updates:   2,
+				Users:     r.flag.Users,
+				Ventures:  r.flag.Ventures,
+			},
+		}
+
+		mig, err = migrate.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var sta *migrate.State
+	{
+		sta, err = mig.Seed(ctx)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		byt, err := json.MarshalIndent(sta, "", "  ")
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		err = os.WriteFile(r.flag.Out, byt, 0600)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "wrote state of %d ventures and %d keys to %s\n", len(sta.Archives), len(sta.Keyspace), r.flag.Out)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package seed
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "seed"
+	short = "Seed data against the old version of the apiserver."
+	long  = `Seed data against the old version of the apiserver. Every seeded venture is
+exported using the Search APIs and the Redis keyspace is snapshotted once the
+seeded data settled. The resulting state is written to the given file and
+consumed by "cfm migrate verify" once the new version got deployed.
+
+    cfm migrate seed --out state.json
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
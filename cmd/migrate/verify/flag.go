@@ -0,0 +1,27 @@
+package verify
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	Address string
+	In      string
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Address, "address", "a", "127.0.0.1:7777", "The address of the apiserver.")
+	cmd.Flags().StringVarP(&f.In, "in", "i", "", "The file the state is read from.")
+}
+
+func (f *flag) Validate() error {
+	if f.Address == "" {
+		return tracer.Maskf(invalidFlagError, "-a/--address must not be empty")
+	}
+	if f.In == "" {
+		return tracer.Maskf(invalidFlagError, "-i/--in must not be empty")
+	}
+
+	return nil
+}
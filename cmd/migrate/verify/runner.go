@@ -0,0 +1,81 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/migrate"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	sta := &migrate.State{}
+	{
+		byt, err := os.ReadFile(r.flag.In)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		err = json.Unmarshal(byt, sta)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var mig *migrate.Migrate
+	{
+		c := migrate.Config{
+			Address: r.flag.Address,
+		}
+
+		mig, err = migrate.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var rep *migrate.Report
+	{
+		rep, err = mig.Verify(ctx, sta)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		byt, err := json.MarshalIndent(rep, "", "  ")
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), string(byt))
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package verify
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "verify"
+	short = "Verify seeded data against the new version of the apiserver."
+	long  = `Verify seeded data against the new version of the apiserver. Every seeded
+object is re-read using the Search APIs and must match the state recorded by
+"cfm migrate seed", including IDs, properties, metadata and roles. Members
+must still be granted access and deleting ventures must still cascade. Changes
+of the Redis keyspace are reported. Note that verification deletes parts of
+the seeded data.
+
+    cfm migrate verify --in state.json
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,39 @@
+package run
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/label"
+)
+
+type flag struct {
+	Labels         string
+	List           bool
+	Nondestructive bool
+	Path           string
+	Smoke          bool
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Labels, "labels", "l", "", "Labels selecting the tests to run, e.g. resource=invite,destructive=false.")
+	cmd.Flags().BoolVarP(&f.List, "list", "", false, "Whether to list the selected tests instead of running them.")
+	cmd.Flags().BoolVarP(&f.Nondestructive, "nondestructive", "n", false, "Whether to run against a shared environment without touching Redis.")
+	cmd.Flags().StringVarP(&f.Path, "path", "p", "./tst", "The path of the conformance test package.")
+	cmd.Flags().BoolVarP(&f.Smoke, "smoke", "s", false, "Whether to select the smoke subset, which implies --nondestructive.")
+}
+
+func (f *flag) Validate() error {
+	_, err := label.Parse(f.Labels)
+	if label.IsInvalidFilter(err) {
+		return tracer.Maskf(invalidFlagError, "-l/--labels %s", tracer.Cause(err))
+	} else if err != nil {
+		return tracer.Mask(err)
+	}
+
+	if f.Path == "" {
+		return tracer.Maskf(invalidFlagError, "-p/--path must not be empty")
+	}
+
+	return nil
+}
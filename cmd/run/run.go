@@ -0,0 +1,54 @@
+package run
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "run"
+	short = "Run conformance tests selected by their labels."
+	long  = `Run conformance tests selected by their labels. Every conformance test is
+labelled with the resource it is about, its category and whether it is
+destructive. Values of the same label are combined using OR, different labels
+are combined using AND. Arguments following -- are passed to go test.
+
+    cfm run --labels resource=invite
+    cfm run --labels category=cascade -- -v -args -wait 60s
+    cfm run --labels resource=invite,resource=role,category=negative --list
+
+Tests run in non destructive mode against shared environments. Destructive
+tests are skipped, Redis is not accessed and every resource created by a test
+is deleted via the API once the test completed.
+
+    cfm run --nondestructive
+
+The smoke subset is fast and runs in non destructive mode, and thus is safe
+for production.
+
+    cfm run --smoke
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name + " [-- go test flags]",
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
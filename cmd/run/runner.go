@@ -0,0 +1,121 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/label"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	var fil label.Filter
+	{
+		fil, err = label.Parse(r.flag.Labels)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if r.flag.Nondestructive || r.flag.Smoke {
+			for k, v := range label.Nondestructive() {
+				fil[k] = v
+			}
+		}
+
+		if r.flag.Smoke {
+			for k, v := range label.Smoke() {
+				fil[k] = v
+			}
+		}
+	}
+
+	lis := fil.Tests()
+
+	if r.flag.List {
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+
+		fmt.Fprintln(w, "TEST\tRESOURCE\tCATEGORY\tDESTRUCTIVE\tSMOKE")
+		for _, n := range lis {
+			l := label.Catalog[n]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%t\n", n, l.Resource, l.Category, l.Destructive, l.Smoke)
+		}
+
+		err = w.Flush()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		return nil
+	}
+
+	if len(lis) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "no tests selected")
+		return nil
+	}
+
+	{
+		arg := []string{"test", "-tags", "conformance", "-count", "1", "-run", label.Pattern(lis), r.flag.Path}
+
+		if r.flag.Nondestructive || r.flag.Smoke {
+			arg = append(arg, nondestructive(args)...)
+		} else {
+			arg = append(arg, args...)
+		}
+
+		c := exec.CommandContext(ctx, "go", arg...)
+		c.Stdin = os.Stdin
+		c.Stdout = cmd.OutOrStdout()
+		c.Stderr = cmd.ErrOrStderr()
+
+		err = c.Run()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// nondestructive adds the -nondestructive flag of the test binary to the
+// arguments given to go test. Test binary flags follow -args, which is added
+// unless given already.
+func nondestructive(args []string) []string {
+	for i, a := range args {
+		if a == "-args" || a == "--args" {
+			var lis []string
+			lis = append(lis, args[:i+1]...)
+			lis = append(lis, "-nondestructive")
+			lis = append(lis, args[i+1:]...)
+			return lis
+		}
+	}
+
+	return append(append([]string{}, args...), "-args", "-nondestructive")
+}
@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type runner struct{}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	cmd.HelpFunc()(cmd, args)
+
+	return nil
+}
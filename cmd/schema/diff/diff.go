@@ -0,0 +1,43 @@
+package diff
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "diff"
+	short = "Report breaking changes between two versions of the protobuf definitions."
+	long  = `Report breaking changes between two versions of the protobuf definitions.
+Versions are either given as serialized descriptor set, as written by "cfm
+schema dump", or as "current", which refers to the apigengo version cfm is
+compiled against. Every breaking change is classified as wire breaking or
+source breaking. The command fails if any breaking change is found, which
+allows to gate apigengo bumps on compatibility.
+
+    cfm schema diff --old schema/v0.4.1.pb --new current
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
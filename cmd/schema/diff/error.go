@@ -0,0 +1,23 @@
+package diff
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var breakingChangeError = &tracer.Error{
+	Kind: "breakingChangeError",
+}
+
+func IsBreakingChange(err error) bool {
+	return errors.Is(err, breakingChangeError)
+}
+
+var invalidFlagError = &tracer.Error{
+	Kind: "invalidFlagError",
+}
+
+func IsInvalidFlag(err error) bool {
+	return errors.Is(err, invalidFlagError)
+}
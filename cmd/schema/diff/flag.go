@@ -0,0 +1,29 @@
+package diff
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	New  string
+	Old  string
+	Wire bool
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.New, "new", "n", "current", "The new version, either a descriptor set file or current.")
+	cmd.Flags().StringVarP(&f.Old, "old", "o", "", "The old version, either a descriptor set file or current.")
+	cmd.Flags().BoolVarP(&f.Wire, "wire", "w", false, "Whether to only fail on wire breaking changes.")
+}
+
+func (f *flag) Validate() error {
+	if f.New == "" {
+		return tracer.Maskf(invalidFlagError, "-n/--new must not be empty")
+	}
+	if f.Old == "" {
+		return tracer.Maskf(invalidFlagError, "-o/--old must not be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/compat"
+	"github.com/venturemark/cfm/pkg/schema"
+)
+
+const (
+	current = "current"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	var old compat.Version
+	{
+		old, err = version(r.flag.Old)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var new compat.Version
+	{
+		new, err = version(r.flag.New)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var lis []schema.Change
+	{
+		lis = schema.Diff(old, new)
+
+		for _, c := range lis {
+			fmt.Fprintln(cmd.OutOrStdout(), c)
+		}
+	}
+
+	{
+		wir := len(schema.Filter(lis, schema.KindWire))
+		src := len(schema.Filter(lis, schema.KindSource))
+
+		if wir != 0 || (!r.flag.Wire && src != 0) {
+			return tracer.Maskf(breakingChangeError, "%s to %s has %d wire and %d source breaking changes", old.Name, new.Name, wir, src)
+		}
+
+		// Source breaking changes are ignored when only checking the wire, which
+		// must not be reported as if there were no breaking changes at all.
+		if src != 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s to %s has no wire breaking changes (%d source only)\n", old.Name, new.Name, src)
+			return nil
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%s to %s has no breaking changes\n", old.Name, new.Name)
+
+	return nil
+}
+
+func version(s string) (compat.Version, error) {
+	if s == current {
+		return compat.Current(), nil
+	}
+
+	v, err := compat.Load(s)
+	if err != nil {
+		return compat.Version{}, tracer.Mask(err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,41 @@
+package dump
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "dump"
+	short = "Write the current protobuf definitions as serialized descriptor set."
+	long  = `Write the current protobuf definitions as serialized descriptor set. The
+written file can be compared against future versions using "cfm schema diff"
+and checked for backward compatibility using the conformance tests. Record the
+current version before bumping apigengo.
+
+    cfm schema dump --out schema/v0.4.1.pb
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
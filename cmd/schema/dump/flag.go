@@ -0,0 +1,22 @@
+package dump
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	Out string
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Out, "out", "o", "", "The file the descriptor set is written to.")
+}
+
+func (f *flag) Validate() error {
+	if f.Out == "" {
+		return tracer.Maskf(invalidFlagError, "-o/--out must not be empty")
+	}
+
+	return nil
+}
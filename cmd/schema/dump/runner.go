@@ -0,0 +1,50 @@
+package dump
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/compat"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	ver := compat.Current()
+
+	byt, err := ver.Marshal()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = os.WriteFile(r.flag.Out, byt, 0600)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "wrote %s to %s\n", ver.Name, r.flag.Out)
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/cmd/schema/diff"
+	"github.com/venturemark/cfm/cmd/schema/dump"
+)
+
+const (
+	name  = "schema"
+	short = "Inspect the protobuf definitions of the venturemark api."
+	long  = "Inspect the protobuf definitions of the venturemark api."
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var err error
+
+	var cmdDiff *cobra.Command
+	{
+		c := diff.Config{}
+
+		cmdDiff, err = diff.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var cmdDump *cobra.Command
+	{
+		c := dump.Config{}
+
+		cmdDump, err = dump.New(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var c *cobra.Command
+	{
+		r := &runner{}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		c.AddCommand(cmdDiff)
+		c.AddCommand(cmdDump)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,51 @@
+package seed
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+)
+
+type flag struct {
+	Address   string
+	Invites   int
+	Messages  int
+	Out       string
+	Roles     int
+	Seed      int64
+	Teardown  string
+	Timelines int
+	Updates   int
+	Users     int
+	Ventures  int
+}
+
+func (f *flag) Init(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.Address, "address", "a", "127.0.0.1:7777", "The address of the apiserver.")
+	cmd.Flags().IntVarP(&f.Invites, "invites", "", 1, "The number of invites created per venture.")
+	cmd.Flags().IntVarP(&f.Messages, "messages", "", 2, "The number of messages created per update.")
+	cmd.Flags().StringVarP(&f.Out, "out", "o", "", "The file the manifest is written to, defaults to stdout.")
+	cmd.Flags().IntVarP(&f.Roles, "roles", "", 1, "The number of member roles created per venture, granting other seeded users access.")
+	cmd.Flags().Int64VarP(&f.Seed, "seed", "s", 1, "The seed the content is generated from.")
+	cmd.Flags().StringVarP(&f.Teardown, "teardown", "t", "", "The manifest file of seeded data to be deleted instead of seeding.")
+	cmd.Flags().IntVarP(&f.Timelines, "timelines", "", 2, "The number of timelines created per venture.")
+	cmd.Flags().IntVarP(&f.Updates, "updates", "", 3, "The number of text updates created per timeline.")
+	cmd.Flags().IntVarP(&f.Users, "users", "u", 3, "The number of users created.")
+	cmd.Flags().IntVarP(&f.Ventures, "ventures", "", 1, "The number of ventures created per user.")
+}
+
+func (f *flag) Validate() error {
+	if f.Address == "" {
+		return tracer.Maskf(invalidFlagError, "-a/--address must not be empty")
+	}
+	if f.Users < 1 {
+		return tracer.Maskf(invalidFlagError, "-u/--users must be at least 1")
+	}
+	if f.Roles >= f.Users {
+		return tracer.Maskf(invalidFlagError, "--roles must be lower than -u/--users")
+	}
+	if f.Invites < 0 || f.Messages < 0 || f.Roles < 0 || f.Timelines < 0 || f.Updates < 0 || f.Ventures < 0 {
+		return tracer.Maskf(invalidFlagError, "counts must not be negative")
+	}
+
+	return nil
+}
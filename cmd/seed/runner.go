@@ -0,0 +1,110 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/seed"
+)
+
+type runner struct {
+	flag *flag
+}
+
+func (r *runner) Run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	err := r.flag.Validate()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	err = r.run(ctx, cmd, args)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+func (r *runner) run(ctx context.Context, cmd *cobra.Command, args []string) error {
+	var err error
+
+	var see *seed.Seed
+	{
+		c := seed.Config{
+			Address:   r.flag.Address,
+			Invites:   r.flag.Invites,
+			Messages:  r.flag.Messages,
+			Roles:     r.flag.Roles,
+			Seed:      r.flag.Seed,
+			Timelines: r.flag.Timelines,
+			Updates:   r.flag.Updates,
+			Users:     r.flag.Users,
+			Ventures:  r.flag.Ventures,
+		}
+
+		see, err = seed.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if r.flag.Teardown != "" {
+		man := &seed.Manifest{}
+		{
+			byt, err := os.ReadFile(r.flag.Teardown)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			err = json.Unmarshal(byt, man)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+
+		err = see.Delete(ctx, man)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "deleted %d users seeded from %s\n", len(man.Users), r.flag.Teardown)
+
+		return nil
+	}
+
+	var man *seed.Manifest
+	{
+		man, err = see.Create(ctx)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var byt []byte
+	{
+		byt, err = json.MarshalIndent(man, "", "  ")
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if r.flag.Out == "" {
+		fmt.Fprintln(cmd.OutOrStdout(), string(byt))
+	} else {
+		err = os.WriteFile(r.flag.Out, byt, 0600)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "wrote manifest of %d users to %s\n", len(man.Users), r.flag.Out)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package seed
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	name  = "seed"
+	short = "Create realistic data for demos and manual QA."
+	long  = `Create realistic data for demos and manual QA using the public API. Users,
+ventures, timelines, text updates, messages, invites and roles are created with
+varied names and content, which is generated deterministically from the given
+seed. A manifest of all created IDs and the credentials of all seeded users is
+printed, or written to the given file.
+
+    cfm seed --seed 42 --users 5 --out seed.json
+
+The manifest can be used to tear the seeded data down again.
+
+    cfm seed --teardown seed.json
+`
+)
+
+type Config struct{}
+
+func New(config Config) (*cobra.Command, error) {
+	var c *cobra.Command
+	{
+		f := &flag{}
+
+		r := &runner{
+			flag: f,
+		}
+
+		c = &cobra.Command{
+			Use:   name,
+			Short: short,
+			Long:  long,
+			RunE:  r.Run,
+		}
+
+		f.Init(c)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,70 @@
+package archive
+
+const (
+	// Version is the version of the archive format. Archives of other versions
+	// are rejected on import.
+	Version = 1
+)
+
+// Archive is the versioned JSON representation of a single venture including
+// its timelines, updates, messages, roles and invites. All IDs are the IDs of
+// the environment the venture got exported from. Updates and messages are
+// ordered newest first, like the Search APIs return them.
+type Archive struct {
+	Version int `json:"version"`
+	// Schema is the apigengo version the archive got exported with.
+	Schema string `json:"schema"`
+	// Owner is the ID of the user who exported the venture. Roles of the owner
+	// are granted to the importing user.
+	Owner   string  `json:"owner"`
+	Venture Venture `json:"venture"`
+}
+
+type Venture struct {
+	ID        string     `json:"id"`
+	Desc      string     `json:"desc,omitempty"`
+	Link      []Link     `json:"link,omitempty"`
+	Name      string     `json:"name"`
+	Invites   []Invite   `json:"invites,omitempty"`
+	Roles     []Role     `json:"roles,omitempty"`
+	Timelines []Timeline `json:"timelines,omitempty"`
+}
+
+type Link struct {
+	Addr string `json:"addr"`
+	Text string `json:"text"`
+}
+
+type Invite struct {
+	ID   string `json:"id"`
+	Mail string `json:"mail"`
+	Stat string `json:"stat,omitempty"`
+}
+
+type Role struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+}
+
+type Timeline struct {
+	ID      string   `json:"id"`
+	Desc    string   `json:"desc,omitempty"`
+	Name    string   `json:"name"`
+	Stat    string   `json:"stat,omitempty"`
+	Roles   []Role   `json:"roles,omitempty"`
+	Updates []Update `json:"updates,omitempty"`
+}
+
+type Update struct {
+	ID       string    `json:"id"`
+	Head     string    `json:"head,omitempty"`
+	Text     string    `json:"text"`
+	Messages []Message `json:"messages,omitempty"`
+}
+
+type Message struct {
+	ID   string `json:"id"`
+	Reid string `json:"reid,omitempty"`
+	Text string `json:"text"`
+}
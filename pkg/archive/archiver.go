@@ -0,0 +1,43 @@
+package archive
+
+import (
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+)
+
+type Config struct {
+	// Client is used to export and import ventures. Ventures are exported
+	// using the identity of the client, and imported under the identity of
+	// the client as new owner.
+	Client *client.Client
+	// Members causes the roles of subjects other than the exporting user to be
+	// recreated on import, using their original subject IDs. This is only
+	// sensible when importing into the environment the venture got exported
+	// from, since subject IDs of other environments do not resolve.
+	Members bool
+}
+
+// Archiver exports ventures through the Search APIs and imports them through
+// the Create APIs, remapping all IDs. Usage looks like the following.
+//
+//	arc, err := src.Export(ctx, vei)
+//	...
+//	ids, err := dst.Import(ctx, arc)
+type Archiver struct {
+	client  *client.Client
+	members bool
+}
+
+func New(c Config) (*Archiver, error) {
+	if c.Client == nil {
+		return nil, tracer.Maskf(invalidConfigError, "%T.Client must not be empty", c.Client)
+	}
+
+	a := &Archiver{
+		client:  c.Client,
+		members: c.Members,
+	}
+
+	return a, nil
+}
@@ -0,0 +1,39 @@
+package archive
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var invalidArchiveError = &tracer.Error{
+	Kind: "invalidArchiveError",
+}
+
+func IsInvalidArchive(err error) bool {
+	return errors.Is(err, invalidArchiveError)
+}
+
+var invalidConfigError = &tracer.Error{
+	Kind: "invalidConfigError",
+}
+
+func IsInvalidConfig(err error) bool {
+	return errors.Is(err, invalidConfigError)
+}
+
+var notFoundError = &tracer.Error{
+	Kind: "notFoundError",
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, notFoundError)
+}
+
+var rollbackError = &tracer.Error{
+	Kind: "rollbackError",
+}
+
+func IsRollback(err error) bool {
+	return errors.Is(err, rollbackError)
+}
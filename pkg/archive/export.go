@@ -0,0 +1,275 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/update"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/compat"
+)
+
+// Export walks the venture vei using the Search APIs and returns its archive.
+// The venture must be visible to the identity of the client.
+func (a *Archiver) Export(ctx context.Context, vei string) (*Archive, error) {
+	var err error
+
+	arc := &Archive{
+		Version: Version,
+		Schema:  compat.Pinned,
+	}
+
+	{
+		arc.Owner, err = a.user(ctx)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	{
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Venture().Search(ctx, i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		if len(o.Obj) != 1 {
+			return nil, tracer.Maskf(notFoundError, "venture %q", vei)
+		}
+
+		arc.Venture = Venture{
+			ID: vei,
+		}
+
+		if p := o.Obj[0].Property; p != nil {
+			arc.Venture.Desc = p.Desc
+			arc.Venture.Name = p.Name
+
+			for _, l := range p.Link {
+				arc.Venture.Link = append(arc.Venture.Link, Link{Addr: l.Addr, Text: l.Text})
+			}
+		}
+	}
+
+	{
+		met := map[string]string{
+			"resource.venturemark.co/kind": "venture",
+			"venture.venturemark.co/id":    vei,
+		}
+
+		arc.Venture.Roles, err = a.roles(ctx, met)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	{
+		i := &invite.SearchI{
+			Obj: []*invite.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Invite().Search(ctx, i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			v := Invite{
+				ID: x.Metadata["invite.venturemark.co/id"],
+			}
+
+			if x.Property != nil {
+				v.Mail = x.Property.Mail
+				v.Stat = x.Property.Stat
+			}
+
+			arc.Venture.Invites = append(arc.Venture.Invites, v)
+		}
+	}
+
+	{
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Timeline().Search(ctx, i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			t := Timeline{
+				ID: x.Metadata["timeline.venturemark.co/id"],
+			}
+
+			if x.Property != nil {
+				t.Desc = x.Property.Desc
+				t.Name = x.Property.Name
+				t.Stat = x.Property.Stat
+			}
+
+			t, err = a.exportTimeline(ctx, vei, t)
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+
+			arc.Venture.Timelines = append(arc.Venture.Timelines, t)
+		}
+	}
+
+	return arc, nil
+}
+
+// exportTimeline adds the roles, updates and messages of the timeline t within the
+// venture vei.
+func (a *Archiver) exportTimeline(ctx context.Context, vei string, t Timeline) (Timeline, error) {
+	var err error
+
+	{
+		met := map[string]string{
+			"resource.venturemark.co/kind": "timeline",
+			"timeline.venturemark.co/id":   t.ID,
+			"venture.venturemark.co/id":    vei,
+		}
+
+		t.Roles, err = a.roles(ctx, met)
+		if err != nil {
+			return Timeline{}, tracer.Mask(err)
+		}
+	}
+
+	var upd []*update.SearchO_Obj
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": t.ID,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Update().Search(ctx, i)
+		if err != nil {
+			return Timeline{}, tracer.Mask(err)
+		}
+
+		upd = o.Obj
+	}
+
+	for _, x := range upd {
+		u := Update{
+			ID: x.Metadata["update.venturemark.co/id"],
+		}
+
+		if x.Property != nil {
+			u.Head = x.Property.Head
+			u.Text = x.Property.Text
+		}
+
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": t.ID,
+						"update.venturemark.co/id":   u.ID,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Message().Search(ctx, i)
+		if err != nil {
+			return Timeline{}, tracer.Mask(err)
+		}
+
+		for _, y := range o.Obj {
+			m := Message{
+				ID: y.Metadata["message.venturemark.co/id"],
+			}
+
+			if y.Property != nil {
+				m.Reid = y.Property.Reid
+				m.Text = y.Property.Text
+			}
+
+			u.Messages = append(u.Messages, m)
+		}
+
+		t.Updates = append(t.Updates, u)
+	}
+
+	return t, nil
+}
+
+func (a *Archiver) roles(ctx context.Context, met map[string]string) ([]Role, error) {
+	i := &role.SearchI{
+		Obj: []*role.SearchI_Obj{
+			{
+				Metadata: met,
+			},
+		},
+	}
+
+	o, err := a.client.Role().Search(ctx, i)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	var rol []Role
+	for _, x := range o.Obj {
+		rol = append(rol, Role{
+			ID:      x.Metadata["role.venturemark.co/id"],
+			Kind:    x.Metadata["role.venturemark.co/kind"],
+			Subject: x.Metadata["subject.venturemark.co/id"],
+		})
+	}
+
+	return rol, nil
+}
+
+// user returns the ID of the user the client is authenticated as.
+func (a *Archiver) user(ctx context.Context) (string, error) {
+	i := &user.SearchI{}
+
+	o, err := a.client.User().Search(ctx, i)
+	if err != nil {
+		return "", tracer.Mask(err)
+	}
+
+	if len(o.Obj) != 1 {
+		return "", tracer.Maskf(notFoundError, "user of client")
+	}
+
+	return o.Obj[0].Metadata["user.venturemark.co/id"], nil
+}
@@ -0,0 +1,346 @@
+package archive
+
+import (
+	"context"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/compat"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// Import recreates the venture of arc under the identity of the client as
+// new owner and returns the mapping of archived IDs to the IDs of the
+// recreated resources. Updates and messages are created oldest first, so that
+// the recreated venture preserves their order. Timelines get archived once
+// their content got recreated. Invites are recreated as pending. Roles of the
+// exporting user are granted to the importing user, roles of other subjects
+// are only recreated if Config.Members is set. Archives exported with another
+// apigengo version are rejected. The recreated venture is deleted again if any
+// of its content cannot be recreated, so that a failed import leaves nothing
+// behind.
+func (a *Archiver) Import(ctx context.Context, arc *Archive) (map[string]string, error) {
+	if arc.Version != Version {
+		return nil, tracer.Maskf(invalidArchiveError, "version must be %d", Version)
+	}
+	if arc.Schema != compat.Pinned {
+		return nil, tracer.Maskf(invalidArchiveError, "schema must be %s got %s", compat.Pinned, arc.Schema)
+	}
+	if arc.Venture.Name == "" {
+		return nil, tracer.Maskf(invalidArchiveError, "venture name must not be empty")
+	}
+
+	var err error
+
+	ids := map[string]string{}
+
+	{
+		ids[arc.Owner], err = a.user(ctx)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var vei string
+	{
+		var lin []*venture.CreateI_Obj_Property_Link
+		for _, l := range arc.Venture.Link {
+			lin = append(lin, &venture.CreateI_Obj_Property_Link{Addr: l.Addr, Text: l.Text})
+		}
+
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Desc: arc.Venture.Desc,
+						Link: lin,
+						Name: arc.Venture.Name,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Venture().Create(ctx, i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+		ids[arc.Venture.ID] = vei
+	}
+
+	err = a.importVenture(ctx, arc, ids, vei)
+	if err != nil {
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		// The context of the failed import may be cancelled already, which
+		// must not prevent the rollback.
+		_, del := a.client.Venture().Delete(context.Background(), i)
+		if del != nil {
+			return nil, tracer.Maskf(rollbackError, "venture %s must be deleted after failed import: %s: %s", vei, del, err)
+		}
+
+		return nil, tracer.Mask(err)
+	}
+
+	return ids, nil
+}
+
+// importVenture recreates the roles, invites and timelines of the archived
+// venture within the venture vei.
+func (a *Archiver) importVenture(ctx context.Context, arc *Archive, ids map[string]string, vei string) error {
+	var err error
+
+	for _, r := range arc.Venture.Roles {
+		// The importing user owns the recreated venture already.
+		if r.Subject == arc.Owner {
+			continue
+		}
+
+		met := map[string]string{
+			"resource.venturemark.co/kind": "venture",
+			"venture.venturemark.co/id":    vei,
+		}
+
+		err = a.importRole(ctx, arc, ids, met, r)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	for _, v := range arc.Venture.Invites {
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: v.Mail,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Invite().Create(ctx, i)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		ids[v.ID] = o.Obj[0].Metadata["invite.venturemark.co/id"]
+	}
+
+	for _, t := range arc.Venture.Timelines {
+		err = a.importTimeline(ctx, arc, ids, vei, t)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (a *Archiver) importTimeline(ctx context.Context, arc *Archive, ids map[string]string, vei string, t Timeline) error {
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Desc: t.Desc,
+						Name: t.Name,
+					},
+				},
+			},
+		}
+
+		o, err := a.client.Timeline().Create(ctx, i)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		tii = o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		ids[t.ID] = tii
+	}
+
+	{
+		met := map[string]string{
+			"resource.venturemark.co/kind": "timeline",
+			"timeline.venturemark.co/id":   tii,
+			"venture.venturemark.co/id":    vei,
+		}
+
+		// Timelines may grant their creator a role already, which must not be
+		// duplicated.
+		cur, err := a.roles(ctx, met)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		for _, r := range t.Roles {
+			if r.Subject == arc.Owner && hasSubject(cur, ids[arc.Owner]) {
+				continue
+			}
+
+			err = a.importRole(ctx, arc, ids, met, r)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+	}
+
+	for i := len(t.Updates) - 1; i >= 0; i-- {
+		u := t.Updates[i]
+
+		var upi string
+		{
+			i := &texupd.CreateI{
+				Obj: []*texupd.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &texupd.CreateI_Obj_Property{
+							Head: u.Head,
+							Text: u.Text,
+						},
+					},
+				},
+			}
+
+			o, err := a.client.TexUpd().Create(ctx, i)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			upi = o.Obj[0].Metadata["update.venturemark.co/id"]
+			ids[u.ID] = upi
+		}
+
+		for j := len(u.Messages) - 1; j >= 0; j-- {
+			m := u.Messages[j]
+
+			// Replies to messages which are not part of the archive lose their
+			// reference.
+			var rei string
+			if m.Reid != "" {
+				rei = ids[m.Reid]
+			}
+
+			i := &message.CreateI{
+				Obj: []*message.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   upi,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &message.CreateI_Obj_Property{
+							Reid: rei,
+							Text: m.Text,
+						},
+					},
+				},
+			}
+
+			o, err := a.client.Message().Create(ctx, i)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			ids[m.ID] = o.Obj[0].Metadata["message.venturemark.co/id"]
+		}
+	}
+
+	if t.Stat == "archived" {
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{
+							Ope: "replace",
+							Pat: "/obj/property/stat",
+							Val: to.StringP("archived"),
+						},
+					},
+				},
+			},
+		}
+
+		_, err := a.client.Timeline().Update(ctx, i)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// importRole recreates r within the scope described by met. Roles of the
+// exporting user are granted to the importing user. Roles of other subjects
+// are skipped unless Config.Members is set.
+func (a *Archiver) importRole(ctx context.Context, arc *Archive, ids map[string]string, met map[string]string, r Role) error {
+	sub := r.Subject
+	if sub == arc.Owner {
+		sub = ids[arc.Owner]
+	} else if !a.members {
+		return nil
+	}
+
+	cre := map[string]string{
+		"role.venturemark.co/kind":  r.Kind,
+		"subject.venturemark.co/id": sub,
+	}
+	for k, v := range met {
+		cre[k] = v
+	}
+
+	i := &role.CreateI{
+		Obj: []*role.CreateI_Obj{
+			{
+				Metadata: cre,
+			},
+		},
+	}
+
+	o, err := a.client.Role().Create(ctx, i)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	ids[r.ID] = o.Obj[0].Metadata["role.venturemark.co/id"]
+
+	return nil
+}
+
+func hasSubject(rol []Role, sub string) bool {
+	for _, r := range rol {
+		if r.Subject == sub {
+			return true
+		}
+	}
+
+	return false
+}
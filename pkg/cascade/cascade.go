@@ -0,0 +1,188 @@
+package cascade
+
+import (
+	"strings"
+	"time"
+
+	"github.com/xh3b4sd/budget"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/keyspace"
+)
+
+type Config struct {
+	// Budget is used to retry the verification of a deletion, since cascaded
+	// deletion happens asynchronously. Defaults to 9 attempts 5 seconds apart.
+	Budget budget.Interface
+	Client *client.Client
+}
+
+// Cascade verifies that deleting a resource deletes all of its descendants
+// while leaving unrelated resources untouched. Usage looks like the following.
+//
+//	s, err := cas.Snapshot(del, sib...)
+//	...
+//	_, err = cli.Timeline().Delete(...)
+//	...
+//	err = cas.Verify(s)
+type Cascade struct {
+	budget budget.Interface
+	client *client.Client
+}
+
+func New(c Config) (*Cascade, error) {
+	if c.Budget == nil {
+		b, err := budget.NewConstant(budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		c.Budget = b
+	}
+	if c.Client == nil {
+		return nil, tracer.Maskf(invalidConfigError, "%T.Client must not be empty", c.Client)
+	}
+
+	ca := &Cascade{
+		budget: c.Budget,
+		client: c.Client,
+	}
+
+	return ca, nil
+}
+
+// Snapshot captures the state of the resource graph before del gets deleted.
+// That is del and all of its descendants, the siblings given by sib and all
+// of their descendants, as well as the Redis keyspace.
+func (c *Cascade) Snapshot(del Resource, sib ...Resource) (*Snapshot, error) {
+	var err error
+
+	s := &Snapshot{}
+
+	{
+		s.Deleted, err = c.tree(del)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	for _, r := range sib {
+		lis, err := c.tree(r)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		s.Siblings = append(s.Siblings, lis...)
+	}
+
+	{
+		s.Keys, err = keyspace.Search(c.client.Redigo())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	return s, nil
+}
+
+// Verify ensures that every resource captured as deleted within s can neither
+// be found via the Search APIs nor within the Redis keyspace any longer, and
+// that every sibling captured within s is still present. Deleted resources are
+// verified within the configured budget.
+func (c *Cascade) Verify(s *Snapshot) error {
+	var err error
+
+	{
+		o := func() error {
+			for _, r := range s.Deleted {
+				exi, err := c.exists(r)
+				if err != nil {
+					return tracer.Mask(err)
+				}
+
+				if exi {
+					return tracer.Maskf(resourceExistsError, "%s must be deleted", r)
+				}
+			}
+
+			key, err := keyspace.Search(c.client.Redigo())
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			for _, k := range key {
+				r, ok := s.owner(k, s.Deleted)
+				if ok {
+					return tracer.Maskf(resourceExistsError, "key %q of %s must be deleted", k, r)
+				}
+			}
+
+			return nil
+		}
+
+		err = c.budget.Execute(o)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		for _, r := range s.Siblings {
+			exi, err := c.exists(r)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if !exi {
+				return tracer.Maskf(resourceMissingError, "%s must not be deleted", r)
+			}
+		}
+
+		key, err := keyspace.Search(c.client.Redigo())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		for _, k := range keyspace.Removed(s.Keys, key) {
+			_, del := s.owner(k, s.Deleted)
+			if del {
+				continue
+			}
+
+			r, ok := s.owner(k, s.Siblings)
+			if ok {
+				return tracer.Maskf(resourceMissingError, "key %q of %s must not be deleted", k, r)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Snapshot is the state of the resource graph captured before a deletion.
+type Snapshot struct {
+	// Deleted is the resource to be deleted and all of its descendants.
+	Deleted []Resource
+	// Keys is the Redis keyspace before the deletion.
+	Keys []string
+	// Siblings are the resources expected to survive the deletion, including
+	// their descendants.
+	Siblings []Resource
+}
+
+// owner returns the resource of lis whose ID is contained in the Redis key k.
+// Resource IDs are unique across all resources, which makes them suitable to
+// associate keys with resources without knowing the storage layout.
+func (s *Snapshot) owner(k string, lis []Resource) (Resource, bool) {
+	for _, r := range lis {
+		if r.ID() != "" && strings.Contains(k, r.ID()) {
+			return r, true
+		}
+	}
+
+	return Resource{}, false
+}
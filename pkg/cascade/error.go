@@ -0,0 +1,41 @@
+package cascade
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var invalidConfigError = &tracer.Error{
+	Kind: "invalidConfigError",
+}
+
+func IsInvalidConfig(err error) bool {
+	return errors.Is(err, invalidConfigError)
+}
+
+var resourceExistsError = &tracer.Error{
+	Kind: "resourceExistsError",
+}
+
+func IsResourceExists(err error) bool {
+	return errors.Is(err, resourceExistsError)
+}
+
+var resourceMissingError = &tracer.Error{
+	Kind: "resourceMissingError",
+}
+
+func IsResourceMissing(err error) bool {
+	return errors.Is(err, resourceMissingError)
+}
+
+// isAbsent checks whether err indicates that a searched resource does not
+// exist. Searching within the scope of a deleted venture may also be denied,
+// because the roles granting access got deleted together with the venture.
+func isAbsent(err error) bool {
+	c := status.Code(tracer.Cause(err))
+	return err != nil && (c == codes.NotFound || c == codes.PermissionDenied)
+}
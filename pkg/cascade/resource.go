@@ -0,0 +1,103 @@
+package cascade
+
+const (
+	KindInvite   = "invite"
+	KindMessage  = "message"
+	KindRole     = "role"
+	KindTimeline = "timeline"
+	KindUpdate   = "update"
+	KindVenture  = "venture"
+)
+
+const (
+	keyKind = "resource.venturemark.co/kind"
+)
+
+// children describes the parent to child relationships of all resources.
+// Deleting a resource must delete all of its descendants. Note that roles can
+// be bound to ventures, timelines and invites alike, which is expressed using
+// the resource kind metadata.
+var children = map[string][]string{
+	KindInvite:   {KindRole},
+	KindTimeline: {KindUpdate, KindRole},
+	KindUpdate:   {KindMessage},
+	KindVenture:  {KindTimeline, KindInvite, KindRole},
+}
+
+// path describes the IDs required in order to address a resource of the given
+// kind, ordered from the root of the graph to the resource itself. Roles are
+// addressed by the path of the resource they are bound to.
+var path = map[string][]string{
+	KindInvite:   {KindVenture, KindInvite},
+	KindMessage:  {KindVenture, KindTimeline, KindUpdate, KindMessage},
+	KindTimeline: {KindVenture, KindTimeline},
+	KindUpdate:   {KindVenture, KindTimeline, KindUpdate},
+	KindVenture:  {KindVenture},
+}
+
+// Resource identifies a single resource of the resource graph.
+type Resource struct {
+	// Kind is the kind of the resource, e.g. KindTimeline.
+	Kind string
+	// Metadata is the metadata required in order to address the resource, e.g.
+	// the timeline ID and the venture ID for timelines. Roles additionally
+	// require the resource kind they are bound to.
+	Metadata map[string]string
+}
+
+// ID returns the ID of the resource, e.g. the value of the metadata key
+// timeline.venturemark.co/id for timelines.
+func (r Resource) ID() string {
+	return r.Metadata[idKey(r.Kind)]
+}
+
+func (r Resource) String() string {
+	return r.Kind + " " + r.ID()
+}
+
+// child returns the metadata used to search for children of the given kind
+// within r.
+func (r Resource) child(kin string) map[string]string {
+	met := pick(r.Metadata, path[r.Kind])
+
+	if kin == KindRole {
+		met[keyKind] = r.Kind
+	}
+
+	return met
+}
+
+// scope returns the metadata used to search for r itself. Ventures are
+// searched by their own ID, all other resources within the scope of their
+// parent.
+func (r Resource) scope() map[string]string {
+	if r.Kind == KindVenture {
+		return pick(r.Metadata, path[KindVenture])
+	}
+
+	if r.Kind == KindRole {
+		met := pick(r.Metadata, path[r.Metadata[keyKind]])
+		met[keyKind] = r.Metadata[keyKind]
+		return met
+	}
+
+	p := path[r.Kind]
+	return pick(r.Metadata, p[:len(p)-1])
+}
+
+func idKey(kin string) string {
+	return kin + ".venturemark.co/id"
+}
+
+func pick(met map[string]string, kin []string) map[string]string {
+	cop := map[string]string{}
+
+	for _, k := range kin {
+		v, ok := met[idKey(k)]
+		if ok {
+			cop[idKey(k)] = v
+		}
+	}
+
+	return cop
+}
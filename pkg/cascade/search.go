@@ -0,0 +1,179 @@
+package cascade
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/update"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/tracer"
+)
+
+// search lists all resources of the given kind within the scope described by
+// met using the Search API of the respective service. The returned resources
+// carry the scope metadata merged with the metadata of the search results.
+func (c *Cascade) search(kin string, met map[string]string) ([]Resource, error) {
+	var lis []map[string]string
+
+	switch kin {
+	case KindInvite:
+		i := &invite.SearchI{
+			Obj: []*invite.SearchI_Obj{
+				{Metadata: met},
+			},
+		}
+
+		o, err := c.client.Invite().Search(context.Background(), i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			lis = append(lis, x.Metadata)
+		}
+	case KindMessage:
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{Metadata: met},
+			},
+		}
+
+		o, err := c.client.Message().Search(context.Background(), i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			lis = append(lis, x.Metadata)
+		}
+	case KindRole:
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{Metadata: met},
+			},
+		}
+
+		o, err := c.client.Role().Search(context.Background(), i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			lis = append(lis, x.Metadata)
+		}
+	case KindTimeline:
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{Metadata: met},
+			},
+		}
+
+		o, err := c.client.Timeline().Search(context.Background(), i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			lis = append(lis, x.Metadata)
+		}
+	case KindUpdate:
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{Metadata: met},
+			},
+		}
+
+		o, err := c.client.Update().Search(context.Background(), i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			lis = append(lis, x.Metadata)
+		}
+	case KindVenture:
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{Metadata: met},
+			},
+		}
+
+		o, err := c.client.Venture().Search(context.Background(), i)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range o.Obj {
+			lis = append(lis, x.Metadata)
+		}
+	default:
+		return nil, tracer.Mask(fmt.Errorf("resource kind %q must be known", kin))
+	}
+
+	var res []Resource
+	for _, l := range lis {
+		r := Resource{
+			Kind:     kin,
+			Metadata: map[string]string{},
+		}
+
+		for k, v := range met {
+			r.Metadata[k] = v
+		}
+		for k, v := range l {
+			r.Metadata[k] = v
+		}
+
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// exists checks whether r can still be found using the Search API of the
+// respective service. Searches rejected because the resource, or any of its
+// parents, is absent count as r not existing.
+func (c *Cascade) exists(r Resource) (bool, error) {
+	lis, err := c.search(r.Kind, r.scope())
+	if isAbsent(err) {
+		return false, nil
+	} else if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	for _, x := range lis {
+		if x.ID() == r.ID() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// tree returns r and all of its descendants, walking the resource graph depth
+// first.
+func (c *Cascade) tree(r Resource) ([]Resource, error) {
+	res := []Resource{r}
+
+	for _, k := range children[r.Kind] {
+		lis, err := c.search(k, r.child(k))
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, x := range lis {
+			des, err := c.tree(x)
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+
+			res = append(res, des...)
+		}
+	}
+
+	return res, nil
+}
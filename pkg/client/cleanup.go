@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+const (
+	kindInvite   = "invite"
+	kindMessage  = "message"
+	kindRole     = "role"
+	kindTimeline = "timeline"
+	kindUpdate   = "update"
+	kindUser     = "user"
+	kindVenture  = "venture"
+)
+
+// resource describes a resource created through the client. Its metadata is
+// the metadata of the create request merged with the metadata of the create
+// response, which is what is required in order to delete the resource again.
+type resource struct {
+	kind     string
+	metadata map[string]string
+}
+
+func (r resource) String() string {
+	return r.kind + " " + r.metadata[r.kind+".venturemark.co/id"]
+}
+
+// created tracks the resources created through a client, in the order they
+// got created.
+type created struct {
+	mutex sync.Mutex
+	list  []resource
+}
+
+func (c *created) add(kin string, inp []map[string]string, out []map[string]string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, o := range out {
+		met := map[string]string{}
+
+		if i < len(inp) {
+			for k, v := range inp[i] {
+				met[k] = v
+			}
+		}
+		for k, v := range o {
+			met[k] = v
+		}
+
+		c.list = append(c.list, resource{kind: kin, metadata: met})
+	}
+}
+
+// reverse returns all resources created so far, most recent first.
+func (c *created) reverse() []resource {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var lis []resource
+	for i := len(c.list) - 1; i >= 0; i-- {
+		lis = append(lis, c.list[i])
+	}
+
+	return lis
+}
+
+// creates maps the Create methods of all services to the kind of resource they
+// create.
+var creates = map[string]string{
+	"/invite.API/Create":   kindInvite,
+	"/message.API/Create":  kindMessage,
+	"/role.API/Create":     kindRole,
+	"/texupd.API/Create":   kindUpdate,
+	"/timeline.API/Create": kindTimeline,
+	"/user.API/Create":     kindUser,
+	"/venture.API/Create":  kindVenture,
+}
+
+// recording returns a unary interceptor registering every resource created
+// successfully with cre. Requests and responses are inspected using protobuf
+// reflection, so that resources created using dynamic messages, e.g. by
+// compat.Version.Call, are registered as well.
+func recording(cre *created) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, met string, req, res interface{}, con *grpc.ClientConn, inv grpc.UnaryInvoker, opt ...grpc.CallOption) error {
+		err := inv(ctx, met, req, res, con, opt...)
+		if err != nil {
+			return err
+		}
+
+		kin, ok := creates[met]
+		if !ok {
+			return nil
+		}
+
+		i, ok := req.(proto.Message)
+		if !ok {
+			return nil
+		}
+		o, ok := res.(proto.Message)
+		if !ok {
+			return nil
+		}
+
+		cre.add(kin, objects(i), objects(o))
+
+		return nil
+	}
+}
+
+// objects returns the metadata of every object of the given request or
+// response, in order.
+func objects(msg proto.Message) []map[string]string {
+	ref := msg.ProtoReflect()
+
+	obj := ref.Descriptor().Fields().ByName("obj")
+	if obj == nil || !obj.IsList() || obj.Message() == nil {
+		return nil
+	}
+
+	var lis []map[string]string
+
+	val := ref.Get(obj).List()
+	for i := 0; i < val.Len(); i++ {
+		x := val.Get(i).Message()
+		met := map[string]string{}
+
+		fie := x.Descriptor().Fields().ByName("metadata")
+		if fie != nil && fie.IsMap() {
+			x.Get(fie).Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				met[k.String()] = v.String()
+				return true
+			})
+		}
+
+		lis = append(lis, met)
+	}
+
+	return lis
+}
+
+// cleanup deletes every resource created through the client, most recent
+// first, so that children are deleted before their parents. Resources deleted
+// already, e.g. by the caller or by cascaded deletion, are skipped. cleanup
+// returns a description of every resource which could not be deleted.
+func (c *Client) cleanup() []string {
+	var lis []string
+
+	for _, r := range c.created.reverse() {
+		err := c.delete(r)
+		if isAbsent(err) {
+			continue
+		} else if err != nil {
+			lis = append(lis, fmt.Sprintf("%s must be deleted: %s", r, err))
+		}
+	}
+
+	return lis
+}
+
+func (c *Client) delete(r resource) error {
+	var err error
+
+	switch r.kind {
+	case kindInvite:
+		_, err = c.invite.Delete(context.Background(), &invite.DeleteI{Obj: []*invite.DeleteI_Obj{{Metadata: r.metadata}}})
+	case kindMessage:
+		_, err = c.message.Delete(context.Background(), &message.DeleteI{Obj: []*message.DeleteI_Obj{{Metadata: r.metadata}}})
+	case kindRole:
+		_, err = c.role.Delete(context.Background(), &role.DeleteI{Obj: []*role.DeleteI_Obj{{Metadata: r.metadata}}})
+	case kindTimeline:
+		err = c.archive(r)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.timeline.Delete(context.Background(), &timeline.DeleteI{Obj: []*timeline.DeleteI_Obj{{Metadata: r.metadata}}})
+	case kindUpdate:
+		_, err = c.texupd.Delete(context.Background(), &texupd.DeleteI{Obj: []*texupd.DeleteI_Obj{{Metadata: r.metadata}}})
+	case kindUser:
+		_, err = c.user.Delete(context.Background(), &user.DeleteI{Obj: []*user.DeleteI_Obj{{Metadata: r.metadata}}})
+	case kindVenture:
+		_, err = c.venture.Delete(context.Background(), &venture.DeleteI{Obj: []*venture.DeleteI_Obj{{Metadata: r.metadata}}})
+	}
+
+	return err
+}
+
+// archive archives the given timeline, since only archived timelines can be
+// deleted. Archiving a timeline which got archived already succeeds.
+func (c *Client) archive(r resource) error {
+	i := &timeline.UpdateI{
+		Obj: []*timeline.UpdateI_Obj{
+			{
+				Metadata: r.metadata,
+				Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+					{
+						Ope: "replace",
+						Pat: "/obj/property/stat",
+						Val: to.StringP("archived"),
+					},
+				},
+			},
+		},
+	}
+
+	_, err := c.timeline.Update(context.Background(), i)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
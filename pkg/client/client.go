@@ -1,6 +1,11 @@
 package client
 
 import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/venturemark/apigengo/pkg/pbf/invite"
 	"github.com/venturemark/apigengo/pkg/pbf/message"
 	"github.com/venturemark/apigengo/pkg/pbf/role"
@@ -12,6 +17,7 @@ import (
 	"github.com/xh3b4sd/redigo"
 	"github.com/xh3b4sd/redigo/pkg/client"
 	"github.com/xh3b4sd/tracer"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
@@ -19,13 +25,46 @@ import (
 )
 
 type Config struct {
-	Address     string
+	Address string
+	// Correlation is the optional correlation ID sent as gRPC metadata with
+	// every request, e.g. the name of the test using the client.
+	Correlation string
 	Credentials credentials.PerRPCCredentials
+	// Logger is optionally used to log every RPC as structured JSON.
+	Logger Logger
+	// Near is optionally called for every RPC which took at least 80% of its
+	// deadline, which makes slow endpoints visible before they time out.
+	Near func(Near)
+	// Nondestructive makes the client safe to use against shared environments.
+	// Wait does not check Redis, and Close deletes every resource created
+	// through the client, most recent first, before closing the connections.
+	// Callers must not use Redigo then.
+	Nondestructive bool
+	// Span is the optional parent span of every RPC, e.g. the span of the test
+	// using the client. RPCs are traced and their trace context is propagated
+	// if Span is set.
+	Span trace.Span
+	// Timeout is the deadline applied to every RPC whose context has no
+	// deadline yet. Defaults to 30 seconds.
+	Timeout time.Duration
+	// Timeouts optionally overrides Timeout per service, e.g. "timeline", or
+	// per method, e.g. "/timeline.API/Search".
+	Timeouts map[string]time.Duration
+	// Wait optionally blocks New until the gRPC channel is READY, the gRPC
+	// health checking protocol of the apiserver reports SERVING and Redis
+	// answers PING. New fails with an error describing the first check not
+	// succeeding within Wait. New does not block if Wait is zero.
+	Wait time.Duration
 }
 
 type Client struct {
-	grpc   *grpc.ClientConn
-	redigo redigo.Interface
+	calls   *inflight
+	created *created
+	grpc    *grpc.ClientConn
+	near    *nearby
+	once    sync.Once
+	redigo  redigo.Interface
+	result  error
 
 	invite   invite.APIClient
 	message  message.APIClient
@@ -44,15 +83,47 @@ func New(c Config) (*Client, error) {
 	if c.Credentials == nil {
 		c.Credentials = oauth.NewInsecureOne()
 	}
+	if c.Timeout == 0 {
+		c.Timeout = 30 * time.Second
+	}
 
 	var err error
 
+	inf := newInflight()
+	nea := &nearby{}
+
+	var cre *created
+	if c.Nondestructive {
+		cre = &created{}
+	}
+
+	var icp []grpc.UnaryClientInterceptor
+	{
+		icp = append(icp, tracking(inf))
+		icp = append(icp, deadline(c.Timeout, c.Timeouts, nea, c.Near))
+
+		if cre != nil {
+			icp = append(icp, recording(cre))
+		}
+
+		if c.Correlation != "" {
+			icp = append(icp, correlation(c.Correlation))
+		}
+		if c.Logger != nil {
+			icp = append(icp, logging(c.Logger, c.Correlation))
+		}
+		if c.Span != nil {
+			icp = append(icp, tracing(c.Span))
+		}
+	}
+
 	var con *grpc.ClientConn
 	{
 		con, err = grpc.Dial(
 			c.Address,
 			grpc.WithInsecure(),
 			grpc.WithPerRPCCredentials(c.Credentials),
+			grpc.WithChainUnaryInterceptor(icp...),
 		)
 		if err != nil {
 			return nil, tracer.Mask(err)
@@ -71,6 +142,15 @@ func New(c Config) (*Client, error) {
 		}
 	}
 
+	if c.Wait != 0 {
+		err = ready(con, red, c.Address, c.Wait, !c.Nondestructive)
+		if err != nil {
+			con.Close()
+			red.Close()
+			return nil, tracer.Mask(err)
+		}
+	}
+
 	var inv invite.APIClient
 	{
 		inv = invite.NewAPIClient(con)
@@ -112,8 +192,11 @@ func New(c Config) (*Client, error) {
 	}
 
 	cli := &Client{
-		grpc:   con,
-		redigo: red,
+		calls:   inf,
+		created: cre,
+		grpc:    con,
+		near:    nea,
+		redigo:  red,
 
 		invite:   inv,
 		message:  mes,
@@ -128,6 +211,43 @@ func New(c Config) (*Client, error) {
 	return cli, nil
 }
 
+// Close closes the gRPC connection and the Redis connection pool owned by the
+// client. Nondestructive clients delete every resource created through them
+// beforehand. RPCs still in flight are reported as error, which allows tests
+// to assert that no RPC outlives the test. Close does not cancel them, but they
+// fail once the gRPC connection got closed. All errors are combined into a
+// single error. Close is idempotent and returns the result of the first call
+// on every call.
+func (c *Client) Close() error {
+	c.once.Do(func() {
+		var lis []string
+
+		for _, x := range c.calls.list() {
+			lis = append(lis, fmt.Sprintf("call %s must not be in flight", x))
+		}
+
+		if c.created != nil {
+			lis = append(lis, c.cleanup()...)
+		}
+
+		err := c.grpc.Close()
+		if err != nil {
+			lis = append(lis, fmt.Sprintf("grpc connection must close: %s", err))
+		}
+
+		err = c.redigo.Close()
+		if err != nil {
+			lis = append(lis, fmt.Sprintf("redis connection must close: %s", err))
+		}
+
+		if len(lis) != 0 {
+			c.result = tracer.Maskf(closeError, "%s", strings.Join(lis, "; "))
+		}
+	})
+
+	return c.result
+}
+
 func (c *Client) Grpc() *grpc.ClientConn {
 	return c.grpc
 }
@@ -136,6 +256,18 @@ func (c *Client) Redigo() redigo.Interface {
 	return c.redigo
 }
 
+// InFlight returns all RPCs of the client which did not return yet, oldest
+// first.
+func (c *Client) InFlight() []Call {
+	return c.calls.list()
+}
+
+// Near returns all RPCs of the client which took at least 80% of their
+// deadline so far, in the order they returned.
+func (c *Client) Near() []Near {
+	return c.near.list()
+}
+
 func (c *Client) Invite() invite.APIClient {
 	return c.invite
 }
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// near is the share of its deadline an RPC must take in order to be
+	// reported as near its deadline.
+	near = 0.8
+)
+
+// Near describes an RPC which took at least 80% of its deadline, including
+// RPCs which exceeded their deadline.
+type Near struct {
+	// Method is the full gRPC method name, e.g. /venture.API/Search.
+	Method string
+	// Duration is the time the RPC took.
+	Duration time.Duration
+	// Deadline is the time the RPC had left when it got invoked.
+	Deadline time.Duration
+}
+
+// nearby collects the RPCs of a client which came near their deadline.
+type nearby struct {
+	mutex sync.Mutex
+	calls []Near
+}
+
+func (n *nearby) add(x Near) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.calls = append(n.calls, x)
+}
+
+func (n *nearby) list() []Near {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return append([]Near(nil), n.calls...)
+}
+
+// deadline returns a unary interceptor applying the timeout of every RPC
+// unless its context has a deadline already. RPCs taking at least 80% of their
+// deadline are recorded with nea and passed to rep, if given.
+func deadline(def time.Duration, ovr map[string]time.Duration, nea *nearby, rep func(Near)) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, met string, req, res interface{}, con *grpc.ClientConn, inv grpc.UnaryInvoker, opt ...grpc.CallOption) error {
+		_, ok := ctx.Deadline()
+		if !ok {
+			var can context.CancelFunc
+			ctx, can = context.WithTimeout(ctx, timeout(met, def, ovr))
+			defer can()
+		}
+
+		dea, _ := ctx.Deadline()
+		lef := time.Until(dea)
+
+		sta := time.Now()
+		err := inv(ctx, met, req, res, con, opt...)
+		dur := time.Since(sta)
+
+		if float64(dur) >= near*float64(lef) {
+			x := Near{
+				Method:   met,
+				Duration: dur,
+				Deadline: lef,
+			}
+
+			nea.add(x)
+
+			if rep != nil {
+				rep(x)
+			}
+		}
+
+		return err
+	}
+}
+
+// timeout returns the timeout of the RPC met. Overrides of the full method
+// name, e.g. /venture.API/Search, take precedence over overrides of the
+// service, e.g. venture, which take precedence over the default def.
+func timeout(met string, def time.Duration, ovr map[string]time.Duration) time.Duration {
+	t, ok := ovr[met]
+	if ok {
+		return t
+	}
+
+	t, ok = ovr[service(met)]
+	if ok {
+		return t
+	}
+
+	return def
+}
+
+// service returns the service of the full gRPC method name met, e.g. venture
+// for /venture.API/Search.
+func service(met string) string {
+	met = strings.TrimPrefix(met, "/")
+
+	i := strings.Index(met, ".")
+	if i == -1 {
+		return met
+	}
+
+	return met[:i]
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var notReadyError = &tracer.Error{
+	Kind: "notReadyError",
+}
+
+func IsNotReady(err error) bool {
+	return errors.Is(err, notReadyError)
+}
+
+var closeError = &tracer.Error{
+	Kind: "closeError",
+}
+
+func IsClose(err error) bool {
+	return errors.Is(err, closeError)
+}
+
+// isAbsent checks whether err indicates that a resource to be deleted does not
+// exist anymore. Denied requests are not considered absent, since they may
+// indicate authorization regressions.
+func isAbsent(err error) bool {
+	return err != nil && status.Code(tracer.Cause(err)) == codes.NotFound
+}
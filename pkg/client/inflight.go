@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Call describes an RPC which did not return yet.
+type Call struct {
+	// Method is the full gRPC method name, e.g. /venture.API/Search.
+	Method string
+	// Start is the time the RPC got invoked.
+	Start time.Time
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s running for %s", c.Method, time.Since(c.Start).Round(time.Millisecond))
+}
+
+// inflight tracks the RPCs of a client which did not return yet.
+type inflight struct {
+	mutex sync.Mutex
+	calls map[uint64]Call
+	next  uint64
+}
+
+func newInflight() *inflight {
+	return &inflight{
+		calls: map[uint64]Call{},
+	}
+}
+
+// list returns all RPCs in flight, oldest first.
+func (i *inflight) list() []Call {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	var lis []Call
+	for _, c := range i.calls {
+		lis = append(lis, c)
+	}
+
+	sort.Slice(lis, func(a, b int) bool { return lis[a].Start.Before(lis[b].Start) })
+
+	return lis
+}
+
+func (i *inflight) add(met string) uint64 {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.next++
+	i.calls[i.next] = Call{Method: met, Start: time.Now()}
+
+	return i.next
+}
+
+func (i *inflight) remove(key uint64) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	delete(i.calls, key)
+}
+
+// tracking returns a unary interceptor registering every RPC with inf for as
+// long as the RPC did not return.
+func tracking(inf *inflight) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, met string, req, res interface{}, con *grpc.ClientConn, inv grpc.UnaryInvoker, opt ...grpc.CallOption) error {
+		key := inf.add(met)
+		defer inf.remove(key)
+
+		return inv(ctx, met, req, res, con, opt...)
+	}
+}
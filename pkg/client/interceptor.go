@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// KeyCorrelation is the gRPC metadata key used to send the correlation ID
+	// with every outgoing request. The apiserver and apiworker logs can be
+	// joined with the conformance tests using this key.
+	KeyCorrelation = "x-correlation-id"
+)
+
+// Logger receives one structured JSON line per RPC. Note that *testing.T
+// satisfies Logger, which causes the RPCs of a test to be printed only in case
+// the test fails, or when running go test -v.
+type Logger interface {
+	Log(args ...interface{})
+}
+
+// entry is the structured log line emitted for every RPC.
+type entry struct {
+	Correlation string          `json:"correlation,omitempty"`
+	Method      string          `json:"method"`
+	Request     json.RawMessage `json:"request"`
+	Response    json.RawMessage `json:"response,omitempty"`
+	Duration    string          `json:"duration"`
+	Deadline    string          `json:"deadline,omitempty"`
+	Status      string          `json:"status"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// correlation returns a unary interceptor attaching the correlation ID cor to
+// the outgoing metadata of every request.
+func correlation(cor string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, met string, req, res interface{}, con *grpc.ClientConn, inv grpc.UnaryInvoker, opt ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, KeyCorrelation, cor)
+		return inv(ctx, met, req, res, con, opt...)
+	}
+}
+
+// logging returns a unary interceptor logging the method, request, response,
+// duration, deadline and status of every RPC as structured JSON using log.
+func logging(log Logger, cor string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, met string, req, res interface{}, con *grpc.ClientConn, inv grpc.UnaryInvoker, opt ...grpc.CallOption) error {
+		sta := time.Now()
+		err := inv(ctx, met, req, res, con, opt...)
+		dur := time.Since(sta)
+
+		e := entry{
+			Correlation: cor,
+			Method:      met,
+			Request:     marshal(req),
+			Duration:    dur.String(),
+			Status:      status.Code(err).String(),
+		}
+
+		dea, ok := ctx.Deadline()
+		if ok {
+			e.Deadline = dea.Sub(sta).String()
+		}
+
+		if err != nil {
+			e.Error = err.Error()
+		} else {
+			e.Response = marshal(res)
+		}
+
+		byt, jer := json.Marshal(e)
+		if jer == nil {
+			log.Log(string(byt))
+		}
+
+		return err
+	}
+}
+
+// tracing returns a unary interceptor starting a client span for every RPC.
+// The span becomes a child of the span found within the request context, or a
+// child of spa if the request context does not carry any span. The W3C trace
+// context of the client span is propagated using the outgoing gRPC metadata, so
+// that the spans of the apiserver and apiworker link up under spa.
+func tracing(spa trace.Span) grpc.UnaryClientInterceptor {
+	tra := spa.TracerProvider().Tracer("github.com/venturemark/cfm/pkg/client")
+
+	return func(ctx context.Context, met string, req, res interface{}, con *grpc.ClientConn, inv grpc.UnaryInvoker, opt ...grpc.CallOption) error {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			ctx = trace.ContextWithSpan(ctx, spa)
+		}
+
+		ctx, s := tra.Start(ctx, met, trace.WithSpanKind(trace.SpanKindClient))
+		defer s.End()
+
+		{
+			md, _ := metadata.FromOutgoingContext(ctx)
+			md = md.Copy()
+			propagation.TraceContext{}.Inject(ctx, carrier(md))
+			ctx = metadata.NewOutgoingContext(ctx, md)
+		}
+
+		err := inv(ctx, met, req, res, con, opt...)
+
+		cod := status.Code(err)
+		s.SetAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", met),
+			attribute.String("rpc.grpc.status_code", cod.String()),
+		)
+
+		if cod != codes.OK {
+			s.SetStatus(otelcodes.Error, err.Error())
+		}
+
+		return err
+	}
+}
+
+// carrier adapts gRPC metadata to the text map carrier used for trace context
+// propagation.
+type carrier metadata.MD
+
+func (c carrier) Get(key string) string {
+	v := metadata.MD(c).Get(key)
+	if len(v) == 0 {
+		return ""
+	}
+
+	return v[0]
+}
+
+func (c carrier) Set(key string, val string) {
+	metadata.MD(c).Set(key, val)
+}
+
+func (c carrier) Keys() []string {
+	var key []string
+	for k := range c {
+		key = append(key, k)
+	}
+
+	return key
+}
+
+// marshal returns the JSON representation of the given protobuf message. Any
+// message which cannot be marshalled is logged as null, since logging must
+// never fail the RPC.
+func marshal(v interface{}) json.RawMessage {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return json.RawMessage("null")
+	}
+
+	byt, err := protojson.Marshal(m)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+
+	return byt
+}
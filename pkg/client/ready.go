@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xh3b4sd/redigo"
+	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// interval is the duration between readiness checks.
+	interval = 250 * time.Millisecond
+)
+
+// ready blocks until the gRPC channel of con is READY, the gRPC health
+// checking protocol of the apiserver reports SERVING and Redis answers PING,
+// in that order. Redis is only checked if sto is true. ready fails with
+// notReadyError describing the first check not succeeding within wai.
+func ready(con *grpc.ClientConn, red redigo.Interface, add string, wai time.Duration, sto bool) error {
+	ctx, can := context.WithTimeout(context.Background(), wai)
+	defer can()
+
+	{
+		for {
+			s := con.GetState()
+			if s == connectivity.Ready {
+				break
+			}
+
+			// Channels fall back to IDLE after failed connection attempts and
+			// must be told to reconnect.
+			if s == connectivity.Idle {
+				con.Connect()
+			}
+
+			if !con.WaitForStateChange(ctx, s) {
+				return tracer.Maskf(notReadyError, "grpc channel of %s must be READY within %s, state is %s", add, wai, s)
+			}
+		}
+	}
+
+	{
+		cli := grpc_health_v1.NewHealthClient(con)
+
+		o := func() (string, bool) {
+			res, err := cli.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+			if err != nil {
+				return err.Error(), false
+			}
+
+			return "status is " + res.Status.String(), res.Status == grpc_health_v1.HealthCheckResponse_SERVING
+		}
+
+		err := poll(ctx, o)
+		if err != nil {
+			return tracer.Maskf(notReadyError, "grpc health of %s must be SERVING within %s, %s", add, wai, err)
+		}
+	}
+
+	if sto {
+		o := func() (string, bool) {
+			err := red.Check()
+			if err != nil {
+				return err.Error(), false
+			}
+
+			return "", true
+		}
+
+		err := poll(ctx, o)
+		if err != nil {
+			return tracer.Maskf(notReadyError, "redis must answer PING within %s, %s", wai, err)
+		}
+	}
+
+	return nil
+}
+
+// poll executes o every interval until o succeeds or ctx expires. The error
+// returned on expiry carries the description of the last failed attempt.
+func poll(ctx context.Context, o func() (string, bool)) error {
+	for {
+		des, ok := o()
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.New(des)
+		case <-time.After(interval):
+		}
+	}
+}
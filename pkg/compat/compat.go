@@ -0,0 +1,223 @@
+package compat
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	_ "github.com/venturemark/apigengo/pkg/pbf/invite"
+	_ "github.com/venturemark/apigengo/pkg/pbf/message"
+	_ "github.com/venturemark/apigengo/pkg/pbf/role"
+	_ "github.com/venturemark/apigengo/pkg/pbf/texupd"
+	_ "github.com/venturemark/apigengo/pkg/pbf/timeline"
+	_ "github.com/venturemark/apigengo/pkg/pbf/update"
+	_ "github.com/venturemark/apigengo/pkg/pbf/user"
+	_ "github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	// Pinned is the apigengo version cfm is compiled against, which must match
+	// the version required by go.mod.
+	Pinned = "v0.4.1"
+)
+
+const (
+	// prefix is the path prefix of all apigengo proto files.
+	prefix = "pbf/"
+)
+
+// Version is a set of protobuf definitions of the venturemark API, e.g. the
+// definitions of a certain apigengo release. Requests are built from the
+// descriptors of the version at runtime, which allows clients of several
+// versions to talk to the same server on the wire. Usage looks like the
+// following.
+//
+//	ver, err := compat.Load("v0.3.0.pb")
+//	...
+//	res, err := ver.Call(ctx, cli.Grpc(), "venture.API/Search", `{"obj":[...]}`)
+type Version struct {
+	// Name is the name of the version, e.g. v0.4.1.
+	Name  string
+	files *protoregistry.Files
+}
+
+// Current returns the version of the protobuf definitions compiled into cfm.
+func Current() Version {
+	return Version{
+		Name:  Pinned,
+		files: protoregistry.GlobalFiles,
+	}
+}
+
+// Load reads a serialized FileDescriptorSet as for instance generated by the
+// following command within a checkout of the respective apigengo release. The
+// version is named after the file name without extension.
+//
+//	protoc --include_imports --descriptor_set_out v0.3.0.pb pbf/*/*.proto
+func Load(pat string) (Version, error) {
+	byt, err := os.ReadFile(pat)
+	if err != nil {
+		return Version{}, tracer.Mask(err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	err = proto.Unmarshal(byt, set)
+	if err != nil {
+		return Version{}, tracer.Maskf(invalidDescriptorError, err.Error())
+	}
+
+	fil, err := protodesc.NewFiles(set)
+	if err != nil {
+		return Version{}, tracer.Maskf(invalidDescriptorError, err.Error())
+	}
+
+	v := Version{
+		Name:  strings.TrimSuffix(filepath.Base(pat), filepath.Ext(pat)),
+		files: fil,
+	}
+
+	return v, nil
+}
+
+// Glob loads all versions matching the given file pattern, e.g. dir/*.pb,
+// sorted by their name.
+func Glob(pat string) ([]Version, error) {
+	mat, err := filepath.Glob(pat)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	sort.Strings(mat)
+
+	var lis []Version
+	for _, m := range mat {
+		v, err := Load(m)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		lis = append(lis, v)
+	}
+
+	return lis, nil
+}
+
+// Call invokes the method met, e.g. venture.API/Search, using con. The request
+// given by req and the returned response are JSON encoded according to the
+// protobuf JSON mapping of the version's definitions.
+func (v Version) Call(ctx context.Context, con grpc.ClientConnInterface, met string, req string) (string, error) {
+	md, err := v.Method(met)
+	if err != nil {
+		return "", tracer.Mask(err)
+	}
+
+	inp := dynamicpb.NewMessage(md.Input())
+	{
+		err = protojson.Unmarshal([]byte(req), inp)
+		if err != nil {
+			return "", tracer.Mask(err)
+		}
+	}
+
+	out := dynamicpb.NewMessage(md.Output())
+	{
+		err = con.Invoke(ctx, "/"+met, inp, out)
+		if err != nil {
+			return "", tracer.Mask(err)
+		}
+	}
+
+	byt, err := protojson.Marshal(out)
+	if err != nil {
+		return "", tracer.Mask(err)
+	}
+
+	return string(byt), nil
+}
+
+// Files returns the descriptors of all venturemark proto files of the version.
+func (v Version) Files() []protoreflect.FileDescriptor {
+	var lis []protoreflect.FileDescriptor
+
+	v.files.RangeFiles(func(f protoreflect.FileDescriptor) bool {
+		if strings.HasPrefix(f.Path(), prefix) {
+			lis = append(lis, f)
+		}
+
+		return true
+	})
+
+	sort.Slice(lis, func(i, j int) bool { return lis[i].Path() < lis[j].Path() })
+
+	return lis
+}
+
+// Marshal serializes the version as FileDescriptorSet, which can be read using
+// Load. Recording the pinned version before bumping apigengo keeps the old
+// definitions available to the compatibility matrix.
+func (v Version) Marshal() ([]byte, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+
+	see := map[string]bool{}
+	var add func(f protoreflect.FileDescriptor)
+	add = func(f protoreflect.FileDescriptor) {
+		if see[f.Path()] {
+			return
+		}
+		see[f.Path()] = true
+
+		imp := f.Imports()
+		for i := 0; i < imp.Len(); i++ {
+			add(imp.Get(i).FileDescriptor)
+		}
+
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(f))
+	}
+
+	for _, f := range v.Files() {
+		add(f)
+	}
+
+	byt, err := proto.Marshal(set)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	return byt, nil
+}
+
+// Method returns the descriptor of the method met, e.g. venture.API/Search.
+func (v Version) Method(met string) (protoreflect.MethodDescriptor, error) {
+	spl := strings.Split(met, "/")
+	if len(spl) != 2 {
+		return nil, tracer.Maskf(methodNotFoundError, "method %q must be of the form service/method", met)
+	}
+
+	des, err := v.files.FindDescriptorByName(protoreflect.FullName(spl[0]))
+	if err != nil {
+		return nil, tracer.Maskf(methodNotFoundError, "service %q must exist in %s", spl[0], v.Name)
+	}
+
+	sd, ok := des.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, tracer.Maskf(methodNotFoundError, "%q must be a service in %s", spl[0], v.Name)
+	}
+
+	md := sd.Methods().ByName(protoreflect.Name(spl[1]))
+	if md == nil {
+		return nil, tracer.Maskf(methodNotFoundError, "method %q must exist in %s", met, v.Name)
+	}
+
+	return md, nil
+}
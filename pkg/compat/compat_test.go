@@ -0,0 +1,31 @@
+package compat
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// Test_Compat_Pinned ensures that Pinned matches the apigengo version required
+// by go.mod, since archives are only accepted for the pinned version.
+func Test_Compat_Pinned(t *testing.T) {
+	byt, err := os.ReadFile("../../go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ver string
+	for _, l := range strings.Split(string(byt), "\n") {
+		f := strings.Fields(l)
+		if len(f) >= 2 && f[0] == "github.com/venturemark/apigengo" {
+			ver = f[1]
+		}
+	}
+
+	if ver == "" {
+		t.Fatal("go.mod must require github.com/venturemark/apigengo")
+	}
+	if ver != Pinned {
+		t.Fatalf("expected %s got %s", ver, Pinned)
+	}
+}
@@ -0,0 +1,23 @@
+package compat
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var invalidDescriptorError = &tracer.Error{
+	Kind: "invalidDescriptorError",
+}
+
+func IsInvalidDescriptor(err error) bool {
+	return errors.Is(err, invalidDescriptorError)
+}
+
+var methodNotFoundError = &tracer.Error{
+	Kind: "methodNotFoundError",
+}
+
+func IsMethodNotFound(err error) bool {
+	return errors.Is(err, methodNotFoundError)
+}
@@ -0,0 +1,129 @@
+package keyspace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/xh3b4sd/redigo"
+	"github.com/xh3b4sd/tracer"
+)
+
+// Search returns all keys currently stored in Redis, sorted and free of
+// duplicates. Note that SCAN may return the same key multiple times, which is
+// why the result is deduplicated.
+func Search(red redigo.Interface) ([]string, error) {
+	res := make(chan string, 1)
+	ech := make(chan error, 1)
+
+	go func() {
+		defer close(res)
+		ech <- red.Walker().Simple("*", nil, res)
+	}()
+
+	all := map[string]struct{}{}
+	for k := range res {
+		all[k] = struct{}{}
+	}
+
+	err := <-ech
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	var key []string
+	for k := range all {
+		key = append(key, k)
+	}
+
+	sort.Strings(key)
+
+	return key, nil
+}
+
+// Dump returns all keys currently stored in Redis mapped to their values. Keys
+// are read as simple keys first. Keys not holding simple values are read as
+// sorted sets, whose elements are joined by newlines in score order. Comparing
+// two dumps reveals any mutation of the stored data, not only added or removed
+// keys.
+func Dump(red redigo.Interface) (map[string]string, error) {
+	key, err := Search(red)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	dum := map[string]string{}
+	for _, k := range key {
+		val, err := red.Simple().Search().Value(k)
+		if err == nil {
+			dum[k] = val
+			continue
+		}
+
+		lis, err := red.Sorted().Search().Order(k, 0, -1)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		dum[k] = strings.Join(lis, "\n")
+	}
+
+	return dum, nil
+}
+
+// Added returns the keys of aft which do not exist in bef. Both lists are
+// expected to be the result of Search.
+func Added(bef []string, aft []string) []string {
+	return missing(aft, bef)
+}
+
+// Changed returns the keys existing in both bef and aft whose values differ,
+// sorted. Both dumps are expected to be the result of Dump.
+func Changed(bef map[string]string, aft map[string]string) []string {
+	var key []string
+	for k, v := range bef {
+		w, ok := aft[k]
+		if ok && v != w {
+			key = append(key, k)
+		}
+	}
+
+	sort.Strings(key)
+
+	return key
+}
+
+// Keys returns the sorted keys of the given dump, which can be compared using
+// Added and Removed.
+func Keys(dum map[string]string) []string {
+	var key []string
+	for k := range dum {
+		key = append(key, k)
+	}
+
+	sort.Strings(key)
+
+	return key
+}
+
+// Removed returns the keys of bef which do not exist in aft. Both lists are
+// expected to be the result of Search.
+func Removed(bef []string, aft []string) []string {
+	return missing(bef, aft)
+}
+
+func missing(lis []string, oth []string) []string {
+	set := map[string]struct{}{}
+	for _, k := range oth {
+		set[k] = struct{}{}
+	}
+
+	var key []string
+	for _, k := range lis {
+		_, ok := set[k]
+		if !ok {
+			key = append(key, k)
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,382 @@
+package label
+
+// Catalog maps the names of all conformance tests within tst/ to their labels.
+// Every conformance test must be part of the catalog, which is verified by
+// Test_Label_001.
+var Catalog = map[string]Labels{
+	"Fuzz_Invite_Create": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "invite",
+	},
+	"Fuzz_Invite_Update": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "invite",
+	},
+	"Fuzz_Message_Create": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "message",
+	},
+	"Fuzz_Message_Update": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "message",
+	},
+	"Fuzz_Role_Create": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "role",
+	},
+	"Fuzz_Role_Update": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "role",
+	},
+	"Fuzz_TexUpd_Create": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "update",
+	},
+	"Fuzz_TexUpd_Update": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "update",
+	},
+	"Fuzz_Timeline_Create": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "timeline",
+	},
+	"Fuzz_Timeline_Update": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "timeline",
+	},
+	"Fuzz_User_Create": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "user",
+	},
+	"Fuzz_User_Update": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "user",
+	},
+	"Fuzz_Venture_Create": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "venture",
+	},
+	"Fuzz_Venture_Update": {
+		Category:    CategoryFuzz,
+		Destructive: true,
+		Resource:    "venture",
+	},
+	"Test_Archive_001": {
+		Category: CategoryTooling,
+		Resource: "venture",
+	},
+	"Test_Archive_002": {
+		Category: CategoryNegative,
+		Resource: "venture",
+	},
+	"Test_Batch_001": {
+		Category: CategoryBatch,
+		Resource: "timeline",
+	},
+	"Test_Batch_002": {
+		Category:    CategoryBatch,
+		Destructive: true,
+		Resource:    "timeline",
+	},
+	"Test_Batch_003": {
+		Category: CategoryBatch,
+		Resource: "update",
+	},
+	"Test_Batch_004": {
+		Category: CategoryBatch,
+		Resource: "message",
+	},
+	"Test_Batch_005": {
+		Category: CategoryBatch,
+		Resource: "venture",
+	},
+	"Test_Cascade_001": {
+		Category:    CategoryCascade,
+		Destructive: true,
+		Resource:    "venture",
+	},
+	"Test_Cascade_002": {
+		Category:    CategoryCascade,
+		Destructive: true,
+		Resource:    "timeline",
+	},
+	"Test_Cascade_003": {
+		Category:    CategoryCascade,
+		Destructive: true,
+		Resource:    "update",
+	},
+	"Test_Client_001": {
+		Category: CategoryTooling,
+	},
+	"Test_Client_002": {
+		Category: CategoryTooling,
+	},
+	"Test_Client_003": {
+		Category: CategoryTooling,
+	},
+	"Test_Client_004": {
+		Category: CategoryTooling,
+	},
+	"Test_Compat_001": {
+		Category: CategoryCompatibility,
+	},
+	"Test_Idempotency_001": {
+		Category: CategoryIdempotency,
+		Resource: "user",
+	},
+	"Test_Idempotency_002": {
+		Category: CategoryIdempotency,
+		Resource: "venture",
+	},
+	"Test_Idempotency_003": {
+		Category: CategoryIdempotency,
+		Resource: "timeline",
+	},
+	"Test_Idempotency_004": {
+		Category: CategoryIdempotency,
+		Resource: "update",
+	},
+	"Test_Idempotency_005": {
+		Category: CategoryIdempotency,
+		Resource: "message",
+	},
+	"Test_Idempotency_006": {
+		Category: CategoryIdempotency,
+		Resource: "invite",
+	},
+	"Test_Idempotency_007": {
+		Category: CategoryIdempotency,
+		Resource: "role",
+	},
+	"Test_Idempotency_008": {
+		Category: CategoryIdempotency,
+		Resource: "timeline",
+	},
+	"Test_Invite_001": {
+		Category: CategoryLifecycle,
+		Resource: "invite",
+		Smoke:    true,
+	},
+	"Test_Invite_002": {
+		Category: CategoryUniqueness,
+		Resource: "invite",
+	},
+	"Test_Invite_003": {
+		Category: CategoryAuthorization,
+		Resource: "invite",
+	},
+	"Test_Invite_004": {
+		Category: CategoryNegative,
+		Resource: "invite",
+	},
+	"Test_Isolation_001": {
+		Category:    CategoryIsolation,
+		Destructive: true,
+	},
+	"Test_Label_001": {
+		Category: CategoryTooling,
+	},
+	"Test_Message_001": {
+		Category: CategoryLifecycle,
+		Resource: "message",
+		Smoke:    true,
+	},
+	"Test_Message_002": {
+		Category: CategoryNegative,
+		Resource: "message",
+	},
+	"Test_Message_003": {
+		Category: CategorySearch,
+		Resource: "message",
+	},
+	"Test_Message_004": {
+		Category:    CategoryNegative,
+		Destructive: true,
+		Resource:    "message",
+	},
+	"Test_Metrics_001": {
+		Category:    CategoryObservability,
+		Destructive: true,
+	},
+	"Test_Metrics_002": {
+		Category:    CategoryObservability,
+		Destructive: true,
+	},
+	"Test_Migrate_001": {
+		Category:    CategoryTooling,
+		Destructive: true,
+	},
+	"Test_Patch_001": {
+		Category: CategoryPatch,
+		Resource: "timeline",
+	},
+	"Test_Patch_002": {
+		Category: CategoryPatch,
+		Resource: "update",
+	},
+	"Test_Patch_003": {
+		Category: CategoryPatch,
+		Resource: "message",
+	},
+	"Test_Patch_004": {
+		Category: CategoryPatch,
+		Resource: "role",
+	},
+	"Test_Patch_005": {
+		Category: CategoryPatch,
+		Resource: "user",
+	},
+	"Test_Patch_006": {
+		Category: CategoryPatch,
+		Resource: "venture",
+	},
+	"Test_Patch_007": {
+		Category: CategoryPatch,
+		Resource: "invite",
+	},
+	"Test_Role_001": {
+		Category: CategoryLifecycle,
+		Resource: "role",
+		Smoke:    true,
+	},
+	"Test_Role_002": {
+		Category: CategoryNegative,
+		Resource: "role",
+	},
+	"Test_Role_003": {
+		Category: CategoryAuthorization,
+		Resource: "role",
+	},
+	"Test_Role_004": {
+		Category: CategoryAuthorization,
+		Resource: "role",
+	},
+	"Test_Search_001": {
+		Category: CategorySearch,
+		Resource: "update",
+	},
+	"Test_Search_002": {
+		Category: CategorySearch,
+		Resource: "message",
+	},
+	"Test_Search_003": {
+		Category: CategorySearch,
+		Resource: "update",
+	},
+	"Test_Search_004": {
+		Category: CategorySearch,
+		Resource: "update",
+	},
+	"Test_Search_005": {
+		Category: CategorySearch,
+		Resource: "message",
+	},
+	"Test_Search_006": {
+		Category: CategorySearch,
+		Resource: "timeline",
+	},
+	"Test_Seed_001": {
+		Category: CategoryTooling,
+	},
+	"Test_TexUpd_001": {
+		Category: CategoryLifecycle,
+		Resource: "update",
+		Smoke:    true,
+	},
+	"Test_TexUpd_002": {
+		Category: CategoryNegative,
+		Resource: "update",
+	},
+	"Test_TexUpd_003": {
+		Category: CategoryCascade,
+		Resource: "update",
+	},
+	"Test_TexUpd_004": {
+		Category: CategoryNegative,
+		Resource: "update",
+	},
+	"Test_Timeline_001": {
+		Category: CategoryLifecycle,
+		Resource: "timeline",
+		Smoke:    true,
+	},
+	"Test_Timeline_002": {
+		Category: CategoryUniqueness,
+		Resource: "timeline",
+	},
+	"Test_Timeline_003": {
+		Category: CategoryLifecycle,
+		Resource: "timeline",
+	},
+	"Test_Timeline_004": {
+		Category: CategoryNegative,
+		Resource: "timeline",
+	},
+	"Test_Timeline_005": {
+		Category: CategoryAuthorization,
+		Resource: "timeline",
+	},
+	"Test_Timeline_006": {
+		Category: CategoryCascade,
+		Resource: "timeline",
+	},
+	"Test_Timeline_007": {
+		Category: CategoryNegative,
+		Resource: "timeline",
+	},
+	"Test_Timeline_008": {
+		Category: CategoryLifecycle,
+		Resource: "timeline",
+	},
+	"Test_User_001": {
+		Category: CategoryLifecycle,
+		Resource: "user",
+		Smoke:    true,
+	},
+	"Test_User_002": {
+		Category: CategoryNegative,
+		Resource: "user",
+	},
+	"Test_User_003": {
+		Category: CategoryUniqueness,
+		Resource: "user",
+	},
+	"Test_User_004": {
+		Category: CategoryNegative,
+		Resource: "user",
+	},
+	"Test_User_005": {
+		Category: CategoryLifecycle,
+		Resource: "user",
+	},
+	"Test_User_006": {
+		Category: CategoryLifecycle,
+		Resource: "user",
+	},
+	"Test_Venture_001": {
+		Category: CategoryLifecycle,
+		Resource: "venture",
+		Smoke:    true,
+	},
+	"Test_Venture_002": {
+		Category: CategoryNegative,
+		Resource: "venture",
+	},
+	"Test_Venture_003": {
+		Category: CategoryCascade,
+		Resource: "venture",
+	},
+}
@@ -0,0 +1,15 @@
+package label
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var invalidFilterError = &tracer.Error{
+	Kind: "invalidFilterError",
+}
+
+func IsInvalidFilter(err error) bool {
+	return errors.Is(err, invalidFilterError)
+}
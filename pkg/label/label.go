@@ -0,0 +1,167 @@
+package label
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+const (
+	CategoryAuthorization = "authorization"
+	CategoryBatch         = "batch"
+	CategoryCascade       = "cascade"
+	CategoryCompatibility = "compatibility"
+	CategoryFuzz          = "fuzz"
+	CategoryIdempotency   = "idempotency"
+	CategoryIsolation     = "isolation"
+	CategoryLifecycle     = "lifecycle"
+	CategoryNegative      = "negative"
+	CategoryObservability = "observability"
+	CategoryPatch         = "patch"
+	CategorySearch        = "search"
+	CategoryTooling       = "tooling"
+	CategoryUniqueness    = "uniqueness"
+)
+
+const (
+	keyCategory    = "category"
+	keyDestructive = "destructive"
+	keyResource    = "resource"
+	keySmoke       = "smoke"
+)
+
+// Labels describe a single conformance test.
+type Labels struct {
+	// Category is the kind of behaviour the test verifies, e.g.
+	// CategoryLifecycle.
+	Category string
+	// Destructive tests access the storage directly, e.g. in order to audit the
+	// Redis keyspace, or rely on global state like metrics. Destructive tests
+	// can only run against dedicated environments.
+	Destructive bool
+	// Resource is the resource the test is about, e.g. invite. Tests not being
+	// about a single resource have no resource.
+	Resource string
+	// Smoke tests are fast and form the smoke subset, which is run together
+	// with the non destructive filter against production.
+	Smoke bool
+}
+
+// Filter selects conformance tests by their labels. Values of the same key are
+// combined using OR, different keys are combined using AND.
+type Filter map[string][]string
+
+// Parse returns the filter described by s, which is a comma separated list of
+// key value pairs, e.g. the following.
+//
+//	resource=invite,resource=role,category=negative,destructive=false
+func Parse(s string) (Filter, error) {
+	f := Filter{}
+
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		spl := strings.SplitN(p, "=", 2)
+		if len(spl) != 2 {
+			return nil, tracer.Maskf(invalidFilterError, "label %q must be of the form key=val", p)
+		}
+
+		switch spl[0] {
+		case keyCategory, keyResource:
+		case keyDestructive, keySmoke:
+			_, err := strconv.ParseBool(spl[1])
+			if err != nil {
+				return nil, tracer.Maskf(invalidFilterError, "label %q must be true or false", spl[0])
+			}
+		default:
+			return nil, tracer.Maskf(invalidFilterError, "label %q must be one of category, destructive, resource or smoke", spl[0])
+		}
+
+		f[spl[0]] = append(f[spl[0]], spl[1])
+	}
+
+	return f, nil
+}
+
+// Match checks whether l is selected by f. The empty filter matches all
+// labels.
+func (f Filter) Match(l Labels) bool {
+	val := map[string]string{
+		keyCategory:    l.Category,
+		keyDestructive: strconv.FormatBool(l.Destructive),
+		keyResource:    l.Resource,
+		keySmoke:       strconv.FormatBool(l.Smoke),
+	}
+
+	for k, lis := range f {
+		var mat bool
+		for _, v := range lis {
+			if k == keyDestructive || k == keySmoke {
+				b, _ := strconv.ParseBool(v)
+				v = strconv.FormatBool(b)
+			}
+
+			if val[k] == v {
+				mat = true
+			}
+		}
+
+		if !mat {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tests returns the sorted names of all tests of the catalog selected by f.
+func (f Filter) Tests() []string {
+	var lis []string
+	for n, l := range Catalog {
+		if f.Match(l) {
+			lis = append(lis, n)
+		}
+	}
+
+	sort.Strings(lis)
+
+	return lis
+}
+
+// Pattern returns the regular expression matching exactly the given tests,
+// suitable for go test -run. The pattern of no tests matches no test.
+func Pattern(lis []string) string {
+	if len(lis) == 0 {
+		return "^$"
+	}
+
+	var quo []string
+	for _, n := range lis {
+		quo = append(quo, regexp.QuoteMeta(n))
+	}
+
+	return "^(" + strings.Join(quo, "|") + ")$"
+}
+
+// Nondestructive returns the filter selecting all tests which do not access
+// the storage directly, and thus can run against shared environments.
+func Nondestructive() Filter {
+	return Filter{
+		keyDestructive: {"false"},
+	}
+}
+
+// Smoke returns the filter selecting the smoke subset, which is safe to run
+// against production in non destructive mode.
+func Smoke() Filter {
+	return Filter{
+		keyDestructive: {"false"},
+		keySmoke:       {"true"},
+	}
+}
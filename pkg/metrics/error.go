@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var invalidConfigError = &tracer.Error{
+	Kind: "invalidConfigError",
+}
+
+func IsInvalidConfig(err error) bool {
+	return errors.Is(err, invalidConfigError)
+}
+
+var invalidFormatError = &tracer.Error{
+	Kind: "invalidFormatError",
+}
+
+func IsInvalidFormat(err error) bool {
+	return errors.Is(err, invalidFormatError)
+}
+
+var missingMetricError = &tracer.Error{
+	Kind: "missingMetricError",
+}
+
+func IsMissingMetric(err error) bool {
+	return errors.Is(err, missingMetricError)
+}
+
+var unexpectedValueError = &tracer.Error{
+	Kind: "unexpectedValueError",
+}
+
+func IsUnexpectedValue(err error) bool {
+	return errors.Is(err, unexpectedValueError)
+}
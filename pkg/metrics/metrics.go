@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+type Config struct {
+	// Address is the host and port of the metrics endpoint, e.g.
+	// 127.0.0.1:8081 for the apiserver or 127.0.0.1:8082 for the apiworker.
+	Address string
+	// Path is the HTTP path metrics are served at. Defaults to /metrics.
+	Path string
+}
+
+// Scraper reads metrics exposed using the Prometheus text format. Usage looks
+// like the following.
+//
+//	bef, err := scr.Scrape()
+//	...
+//	_, err = cli.Venture().Create(...)
+//	...
+//	aft, err := scr.Scrape()
+//	...
+//	err = metrics.Delta(bef, aft, 1, "grpc_server_handled_total", lab)
+type Scraper struct {
+	client *http.Client
+	url    string
+}
+
+func New(c Config) (*Scraper, error) {
+	if c.Address == "" {
+		return nil, tracer.Maskf(invalidConfigError, "%T.Address must not be empty", c)
+	}
+	if c.Path == "" {
+		c.Path = "/metrics"
+	}
+
+	s := &Scraper{
+		client: &http.Client{Timeout: 5 * time.Second},
+		url:    fmt.Sprintf("http://%s%s", c.Address, c.Path),
+	}
+
+	return s, nil
+}
+
+// Scrape fetches and parses all samples currently exposed by the metrics
+// endpoint.
+func (s *Scraper) Scrape() (Snapshot, error) {
+	res, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, tracer.Maskf(invalidFormatError, "expected status code %d got %d", http.StatusOK, res.StatusCode)
+	}
+
+	byt, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	sna, err := Parse(string(byt))
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	return sna, nil
+}
+
+// Delta ensures that the sum of all samples matching nam and lab changed by
+// exactly exp between the snapshots bef and aft.
+func Delta(bef Snapshot, aft Snapshot, exp float64, nam string, lab map[string]string) error {
+	del := aft.Sum(nam, lab) - bef.Sum(nam, lab)
+	if del != exp {
+		return tracer.Maskf(unexpectedValueError, "expected %s%s to change by %v got %v", nam, format(lab), exp, del)
+	}
+
+	return nil
+}
+
+// Exists ensures that sna exposes at least one sample named nam. Delta and Zero
+// treat metrics which are not exposed at all as zero, which is why a wrong
+// address or metric name is only detected by Exists.
+func Exists(sna Snapshot, nam string) error {
+	for _, x := range sna {
+		if x.Name == nam {
+			return nil
+		}
+	}
+
+	return tracer.Maskf(missingMetricError, "expected %s to be exposed", nam)
+}
+
+// Zero ensures that the sum of all samples matching nam and lab is zero within
+// sna. Series which are not exposed at all count as zero.
+func Zero(sna Snapshot, nam string, lab map[string]string) error {
+	sum := sna.Sum(nam, lab)
+	if sum != 0 {
+		return tracer.Maskf(unexpectedValueError, "expected %s%s to be 0 got %v", nam, format(lab), sum)
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+// Sample is a single sample of the Prometheus text format, e.g.
+//
+//	grpc_server_handled_total{grpc_code="OK",grpc_method="Create"} 3
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Snapshot is the list of all samples exposed by a metrics endpoint at a
+// certain point in time.
+type Snapshot []Sample
+
+// Sum returns the sum of the values of all samples named nam, whose labels
+// contain all labels given by lab. Passing no labels sums up all series of the
+// metric.
+func (s Snapshot) Sum(nam string, lab map[string]string) float64 {
+	var sum float64
+
+	for _, x := range s {
+		if x.Name != nam {
+			continue
+		}
+		if !contains(x.Labels, lab) {
+			continue
+		}
+
+		sum += x.Value
+	}
+
+	return sum
+}
+
+// Parse parses the Prometheus text format. Comments, HELP and TYPE lines are
+// ignored, as well as the optional timestamps of samples.
+func Parse(txt string) (Snapshot, error) {
+	var sna Snapshot
+
+	for i, l := range strings.Split(txt, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		s, err := sample(l)
+		if err != nil {
+			return nil, tracer.Maskf(invalidFormatError, "line %d: %s", i+1, err)
+		}
+
+		sna = append(sna, s)
+	}
+
+	return sna, nil
+}
+
+func contains(lab map[string]string, sub map[string]string) bool {
+	for k, v := range sub {
+		if lab[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func format(lab map[string]string) string {
+	if len(lab) == 0 {
+		return ""
+	}
+
+	var key []string
+	for k := range lab {
+		key = append(key, k)
+	}
+
+	sort.Strings(key)
+
+	var lis []string
+	for _, k := range key {
+		lis = append(lis, k+"="+strconv.Quote(lab[k]))
+	}
+
+	return "{" + strings.Join(lis, ",") + "}"
+}
+
+// sample parses a single sample line of the form
+//
+//	name{key="val",...} value [timestamp]
+func sample(l string) (Sample, error) {
+	s := Sample{
+		Labels: map[string]string{},
+	}
+
+	var rem string
+	{
+		i := strings.IndexAny(l, "{ \t")
+		if i == -1 {
+			return Sample{}, tracer.Maskf(invalidFormatError, "value must not be empty")
+		}
+
+		s.Name = l[:i]
+		rem = l[i:]
+	}
+
+	if strings.HasPrefix(rem, "{") {
+		var err error
+		rem, err = labels(rem[1:], s.Labels)
+		if err != nil {
+			return Sample{}, tracer.Mask(err)
+		}
+	}
+
+	fie := strings.Fields(rem)
+	if len(fie) == 0 || len(fie) > 2 {
+		return Sample{}, tracer.Maskf(invalidFormatError, "value must be followed by optional timestamp only")
+	}
+
+	{
+		v, err := value(fie[0])
+		if err != nil {
+			return Sample{}, tracer.Mask(err)
+		}
+
+		s.Value = v
+	}
+
+	return s, nil
+}
+
+// labels parses the label pairs of a sample into lab, starting right after the
+// opening brace. It returns the remainder of the line following the closing
+// brace. Label values may contain escaped backslashes, quotes and newlines.
+func labels(l string, lab map[string]string) (string, error) {
+	for {
+		l = strings.TrimLeft(l, " ,")
+		if strings.HasPrefix(l, "}") {
+			return l[1:], nil
+		}
+
+		i := strings.Index(l, "=\"")
+		if i == -1 {
+			return "", tracer.Maskf(invalidFormatError, "label must be of the form key=\"val\"")
+		}
+
+		key := strings.TrimSpace(l[:i])
+		l = l[i+2:]
+
+		var val strings.Builder
+		var esc bool
+		end := -1
+		for j, r := range l {
+			if esc {
+				switch r {
+				case 'n':
+					val.WriteRune('\n')
+				default:
+					val.WriteRune(r)
+				}
+				esc = false
+				continue
+			}
+			if r == '\\' {
+				esc = true
+				continue
+			}
+			if r == '"' {
+				end = j
+				break
+			}
+
+			val.WriteRune(r)
+		}
+
+		if end == -1 {
+			return "", tracer.Maskf(invalidFormatError, "label value must be terminated")
+		}
+
+		lab[key] = val.String()
+		l = l[end+1:]
+	}
+}
+
+func value(s string) (float64, error) {
+	switch s {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, tracer.Maskf(invalidFormatError, "value %q must be a number", s)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_Metrics_Parse(t *testing.T) {
+	testCases := []struct {
+		txt string
+		sna Snapshot
+		inv bool
+	}{
+		// Case 0 ensures that empty input results in an empty snapshot.
+		{
+			txt: "",
+			sna: nil,
+		},
+		// Case 1 ensures that HELP, TYPE and comment lines are ignored.
+		{
+			txt: "# HELP grpc_server_handled_total Total number of RPCs.\n# TYPE grpc_server_handled_total counter\n# any comment\ngrpc_server_handled_total 3\n",
+			sna: Snapshot{
+				{Name: "grpc_server_handled_total", Labels: map[string]string{}, Value: 3},
+			},
+		},
+		// Case 2 ensures that labels are parsed.
+		{
+			txt: `grpc_server_handled_total{grpc_code="OK",grpc_method="Create"} 3`,
+			sna: Snapshot{
+				{Name: "grpc_server_handled_total", Labels: map[string]string{"grpc_code": "OK", "grpc_method": "Create"}, Value: 3},
+			},
+		},
+		// Case 3 ensures that escaped backslashes, quotes and newlines within
+		// label values are unescaped.
+		{
+			txt: `metric{pat="C:\\tmp",quo="say \"hi\"",new="one\ntwo"} 1`,
+			sna: Snapshot{
+				{Name: "metric", Labels: map[string]string{"pat": `C:\tmp`, "quo": `say "hi"`, "new": "one\ntwo"}, Value: 1},
+			},
+		},
+		// Case 4 ensures that commas and braces within label values do not end
+		// the label set.
+		{
+			txt: `metric{a="x,y}",b="z"} 1`,
+			sna: Snapshot{
+				{Name: "metric", Labels: map[string]string{"a": "x,y}", "b": "z"}, Value: 1},
+			},
+		},
+		// Case 5 ensures that a trailing comma within the label set is allowed.
+		{
+			txt: `metric{a="x",} 1`,
+			sna: Snapshot{
+				{Name: "metric", Labels: map[string]string{"a": "x"}, Value: 1},
+			},
+		},
+		// Case 6 ensures that the special values +Inf, -Inf and NaN are parsed.
+		{
+			txt: "metric{le=\"+Inf\"} +Inf\nmetric -Inf\nmetric NaN\n",
+			sna: Snapshot{
+				{Name: "metric", Labels: map[string]string{"le": "+Inf"}, Value: math.Inf(1)},
+				{Name: "metric", Labels: map[string]string{}, Value: math.Inf(-1)},
+				{Name: "metric", Labels: map[string]string{}, Value: math.NaN()},
+			},
+		},
+		// Case 7 ensures that timestamps are ignored.
+		{
+			txt: "metric{a=\"x\"} 1.5 1613843069000\nmetric 2e3 1613843069000\n",
+			sna: Snapshot{
+				{Name: "metric", Labels: map[string]string{"a": "x"}, Value: 1.5},
+				{Name: "metric", Labels: map[string]string{}, Value: 2000},
+			},
+		},
+		// Case 8 ensures that a sample without value is rejected.
+		{
+			txt: "metric",
+			inv: true,
+		},
+		// Case 9 ensures that a value that is not a number is rejected.
+		{
+			txt: "metric one",
+			inv: true,
+		},
+		// Case 10 ensures that more than a value and a timestamp is rejected.
+		{
+			txt: "metric 1 1613843069000 extra",
+			inv: true,
+		},
+		// Case 11 ensures that an unterminated label value is rejected.
+		{
+			txt: `metric{a="x} 1`,
+			inv: true,
+		},
+		// Case 12 ensures that a label without quoted value is rejected.
+		{
+			txt: `metric{a=x} 1`,
+			inv: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			sna, err := Parse(tc.txt)
+			if tc.inv {
+				if !IsInvalidFormat(err) {
+					t.Fatalf("expected invalid format error got %#v", err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !snapshotEqual(sna, tc.sna) {
+				t.Fatalf("expected %v got %v", tc.sna, sna)
+			}
+		})
+	}
+}
+
+// snapshotEqual compares the snapshots a and b, treating NaN values as equal,
+// which reflect.DeepEqual does not.
+func snapshotEqual(a Snapshot, b Snapshot) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Name != b[i].Name || !reflect.DeepEqual(a[i].Labels, b[i].Labels) {
+			return false
+		}
+		if math.IsNaN(a[i].Value) && math.IsNaN(b[i].Value) {
+			continue
+		}
+		if a[i].Value != b[i].Value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/archive"
+)
+
+// compare ensures that the venture of cur matches the venture of old exactly,
+// including all IDs. The order of roles, invites and timelines is not part of
+// the API contract, which is why they are compared by ID.
+func compare(old *archive.Archive, cur *archive.Archive) error {
+	o := normalize(old.Venture)
+	c := normalize(cur.Venture)
+
+	{
+		if o.ID != c.ID || o.Name != c.Name || o.Desc != c.Desc || mustJSON(o.Link) != mustJSON(c.Link) {
+			return tracer.Maskf(mismatchError, "properties of venture %s must match", o.ID)
+		}
+		if mustJSON(o.Roles) != mustJSON(c.Roles) {
+			return tracer.Maskf(mismatchError, "roles of venture %s must match", o.ID)
+		}
+		if mustJSON(o.Invites) != mustJSON(c.Invites) {
+			return tracer.Maskf(mismatchError, "invites of venture %s must match", o.ID)
+		}
+		if len(o.Timelines) != len(c.Timelines) {
+			return tracer.Maskf(mismatchError, "timelines of venture %s must match", o.ID)
+		}
+	}
+
+	for i := range o.Timelines {
+		if mustJSON(o.Timelines[i]) != mustJSON(c.Timelines[i]) {
+			return tracer.Maskf(mismatchError, "timeline %s of venture %s must match", o.Timelines[i].ID, o.ID)
+		}
+	}
+
+	return nil
+}
+
+func mustJSON(v interface{}) string {
+	byt, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(byt)
+}
+
+// normalize returns a copy of v with its roles, invites and timelines, as
+// well as the roles of its timelines, sorted by ID.
+func normalize(v archive.Venture) archive.Venture {
+	v.Invites = append([]archive.Invite(nil), v.Invites...)
+	sort.Slice(v.Invites, func(i, j int) bool { return v.Invites[i].ID < v.Invites[j].ID })
+
+	v.Roles = sortRoles(v.Roles)
+
+	v.Timelines = append([]archive.Timeline(nil), v.Timelines...)
+	sort.Slice(v.Timelines, func(i, j int) bool { return v.Timelines[i].ID < v.Timelines[j].ID })
+
+	for i := range v.Timelines {
+		v.Timelines[i].Roles = sortRoles(v.Timelines[i].Roles)
+	}
+
+	return v
+}
+
+func sortRoles(rol []archive.Role) []archive.Role {
+	rol = append([]archive.Role(nil), rol...)
+	sort.Slice(rol, func(i, j int) bool { return rol[i].ID < rol[j].ID })
+
+	return rol
+}
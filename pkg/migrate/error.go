@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+)
+
+var invalidStateError = &tracer.Error{
+	Kind: "invalidStateError",
+}
+
+func IsInvalidState(err error) bool {
+	return errors.Is(err, invalidStateError)
+}
+
+var mismatchError = &tracer.Error{
+	Kind: "mismatchError",
+}
+
+func IsMismatch(err error) bool {
+	return errors.Is(err, mismatchError)
+}
@@ -0,0 +1,427 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/budget"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/archive"
+	"github.com/venturemark/cfm/pkg/cascade"
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/keyspace"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/seed"
+)
+
+const (
+	// quiet is the duration the storage must not change in order to be
+	// considered settled.
+	quiet = 2 * time.Second
+)
+
+type Config struct {
+	// Address is the address of the apiserver. Defaults to the default address
+	// of client.Config.
+	Address string
+	// Budget is used to wait for the seeded data to settle and to verify
+	// cascaded deletion. Defaults to 9 attempts 5 seconds apart.
+	Budget budget.Interface
+	// Seed configures the data seeded against the old version and is only
+	// required for Migrate.Seed. The address of the seed configuration is
+	// overwritten by Address.
+	Seed seed.Config
+}
+
+// Migrate verifies that data written by one version of the apiserver still
+// behaves when served by another version, e.g. after the Redis layout changed.
+// Usage looks like the following.
+//
+//	sta, err := mig.Seed(ctx)
+//	...
+//	// Replace the old apiserver with the new apiserver.
+//	...
+//	rep, err := mig.Verify(ctx, sta)
+type Migrate struct {
+	address string
+	budget  budget.Interface
+	seed    seed.Config
+}
+
+func New(c Config) (*Migrate, error) {
+	if c.Budget == nil {
+		b, err := budget.NewConstant(budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		c.Budget = b
+	}
+
+	c.Seed.Address = c.Address
+
+	m := &Migrate{
+		address: c.Address,
+		budget:  c.Budget,
+		seed:    c.Seed,
+	}
+
+	return m, nil
+}
+
+// Seed seeds data against the old version of the apiserver, exports every
+// seeded venture using the Search APIs and snapshots the Redis keyspace once
+// the seeded data settled.
+func (m *Migrate) Seed(ctx context.Context) (*State, error) {
+	var err error
+
+	sta := &State{}
+
+	{
+		see, err := seed.New(m.seed)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		sta.Manifest, err = see.Create(ctx)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	for _, u := range sta.Manifest.Users {
+		err = m.with(u, func(cli *client.Client) error {
+			arc, err := archive.New(archive.Config{Client: cli})
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			for _, v := range u.Ventures {
+				a, err := arc.Export(ctx, v.ID)
+				if err != nil {
+					return tracer.Mask(err)
+				}
+
+				sta.Archives = append(sta.Archives, a)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	{
+		sta.Keyspace, err = m.settle()
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	return sta, nil
+}
+
+// Verify re-reads every object of sta against the new version of the
+// apiserver. Users must still be resolved from their credentials, ventures
+// must still match their archives exactly, members must still be granted
+// access and deleting the first venture of every user must still cascade. Note
+// that Verify deletes data, which is why it can only be executed once per
+// state. The returned report describes how the Redis keyspace changed.
+func (m *Migrate) Verify(ctx context.Context, sta *State) (*Report, error) {
+	if sta.Manifest == nil || sta.Keyspace == nil {
+		return nil, tracer.Maskf(invalidStateError, "manifest and keyspace must not be empty")
+	}
+
+	var err error
+
+	var rep *Report
+	{
+		rep, err = m.report(sta)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	for _, u := range sta.Manifest.Users {
+		err = m.with(u, func(cli *client.Client) error {
+			o, err := cli.User().Search(ctx, &user.SearchI{})
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if len(o.Obj) != 1 || o.Obj[0].Metadata["user.venturemark.co/id"] != u.ID {
+				return tracer.Maskf(mismatchError, "user %s must be found", u.ID)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	for _, a := range sta.Archives {
+		own, ok := sta.user(a.Owner)
+		if !ok {
+			return nil, tracer.Maskf(invalidStateError, "owner %s must be seeded", a.Owner)
+		}
+
+		err = m.with(own, func(cli *client.Client) error {
+			arc, err := archive.New(archive.Config{Client: cli})
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			cur, err := arc.Export(ctx, a.Venture.ID)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			err = compare(a, cur)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		for _, r := range a.Venture.Roles {
+			if r.Subject == a.Owner {
+				continue
+			}
+
+			mem, ok := sta.user(r.Subject)
+			if !ok {
+				continue
+			}
+
+			err = m.with(mem, func(cli *client.Client) error {
+				return m.member(ctx, cli, a.Venture.ID)
+			})
+			if err != nil {
+				return nil, tracer.Mask(err)
+			}
+		}
+	}
+
+	for _, u := range sta.Manifest.Users {
+		if len(u.Ventures) == 0 {
+			continue
+		}
+
+		err = m.with(u, func(cli *client.Client) error {
+			return m.cascade(ctx, cli, u)
+		})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	return rep, nil
+}
+
+// cascade deletes the first venture of u and verifies that its descendants
+// got deleted, while the other ventures of u survived.
+func (m *Migrate) cascade(ctx context.Context, cli *client.Client, u seed.User) error {
+	var err error
+
+	var cas *cascade.Cascade
+	{
+		c := cascade.Config{
+			Budget: m.budget,
+			Client: cli,
+		}
+
+		cas, err = cascade.New(c)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var del cascade.Resource
+	var sib []cascade.Resource
+	for i, v := range u.Ventures {
+		r := cascade.Resource{
+			Kind: cascade.KindVenture,
+			Metadata: map[string]string{
+				"venture.venturemark.co/id": v.ID,
+			},
+		}
+
+		if i == 0 {
+			del = r
+		} else {
+			sib = append(sib, r)
+		}
+	}
+
+	var sna *cascade.Snapshot
+	{
+		sna, err = cas.Snapshot(del, sib...)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: del.Metadata,
+				},
+			},
+		}
+
+		_, err := cli.Venture().Delete(ctx, i)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		err = cas.Verify(sna)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// member ensures that the member using cli is still granted access to the
+// venture vei.
+func (m *Migrate) member(ctx context.Context, cli *client.Client, vei string) error {
+	i := &venture.SearchI{
+		Obj: []*venture.SearchI_Obj{
+			{
+				Metadata: map[string]string{
+					"venture.venturemark.co/id": vei,
+				},
+			},
+		},
+	}
+
+	o, err := cli.Venture().Search(ctx, i)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	if len(o.Obj) != 1 {
+		return tracer.Maskf(mismatchError, "venture %s must be accessible by members", vei)
+	}
+
+	return nil
+}
+
+func (m *Migrate) report(sta *State) (*Report, error) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli, err = client.New(client.Config{Address: m.address})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		defer cli.Close()
+	}
+
+	var dum map[string]string
+	{
+		dum, err = keyspace.Dump(cli.Redigo())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	rep := &Report{
+		Added:   keyspace.Added(keyspace.Keys(sta.Keyspace), keyspace.Keys(dum)),
+		Changed: keyspace.Changed(sta.Keyspace, dum),
+		Removed: keyspace.Removed(keyspace.Keys(sta.Keyspace), keyspace.Keys(dum)),
+	}
+
+	return rep, nil
+}
+
+// settle returns the Redis dump once it stopped changing for at least quiet.
+// The apiworker processes tasks asynchronously, which is why the storage has
+// to settle before it can be snapshotted. Every comparison waits for quiet
+// first, since the budget executes the first attempt right away.
+func (m *Migrate) settle() (map[string]string, error) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli, err = client.New(client.Config{Address: m.address})
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		defer cli.Close()
+	}
+
+	var dum map[string]string
+	{
+		dum, err = keyspace.Dump(cli.Redigo())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	o := func() error {
+		time.Sleep(quiet)
+
+		cur, err := keyspace.Dump(cli.Redigo())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if !reflect.DeepEqual(dum, cur) {
+			dum = cur
+			return tracer.Mask(fmt.Errorf("storage must settle"))
+		}
+
+		return nil
+	}
+
+	err = m.budget.Execute(o)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	return dum, nil
+}
+
+// with executes fun using a client authenticated as u.
+func (m *Migrate) with(u seed.User, fun func(cli *client.Client) error) error {
+	c := client.Config{
+		Address:     m.address,
+		Credentials: oauth.NewInsecure(u.Mail),
+	}
+
+	cli, err := client.New(c)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	defer cli.Close()
+
+	err = fun(cli)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
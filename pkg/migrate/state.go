@@ -0,0 +1,39 @@
+package migrate
+
+import (
+	"github.com/venturemark/cfm/pkg/archive"
+	"github.com/venturemark/cfm/pkg/seed"
+)
+
+// State is the state of the seeded data as recorded against the old version
+// of the apiserver. The state is written to disk between both phases of the
+// migration.
+type State struct {
+	// Archives are the archives of all seeded ventures, exported by their
+	// owners using the Search APIs.
+	Archives []*archive.Archive `json:"archives"`
+	// Keyspace is the Redis dump taken once the seeded data settled.
+	Keyspace map[string]string `json:"keyspace"`
+	// Manifest describes all seeded resources including the credentials of all
+	// seeded users.
+	Manifest *seed.Manifest `json:"manifest"`
+}
+
+// Report describes how the Redis keyspace changed between both phases of the
+// migration. Changes of the keyspace are expected when the storage layout
+// changes, which is why they are reported rather than rejected.
+type Report struct {
+	Added   []string `json:"added,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+func (s *State) user(usi string) (seed.User, bool) {
+	for _, u := range s.Manifest.Users {
+		if u.ID == usi {
+			return u, true
+		}
+	}
+
+	return seed.User{}, false
+}
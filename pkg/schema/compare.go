@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// compatible lists the groups of scalar kinds which are encoded the same way
+// on the wire. Changing a field between kinds of the same group only breaks
+// the generated code.
+var compatible = [][]protoreflect.Kind{
+	{protoreflect.Int32Kind, protoreflect.Uint32Kind, protoreflect.Int64Kind, protoreflect.Uint64Kind, protoreflect.BoolKind},
+	{protoreflect.Sint32Kind, protoreflect.Sint64Kind},
+	{protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind},
+	{protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind},
+	{protoreflect.StringKind, protoreflect.BytesKind},
+}
+
+func services(old protoreflect.ServiceDescriptor, new protoreflect.ServiceDescriptor) []Change {
+	if new == nil {
+		return []Change{{Kind: KindWire, Path: string(old.FullName()), Text: "service removed"}}
+	}
+
+	var lis []Change
+
+	for i := 0; i < old.Methods().Len(); i++ {
+		o := old.Methods().Get(i)
+		n := new.Methods().ByName(o.Name())
+
+		pat := fmt.Sprintf("%s/%s", old.FullName(), o.Name())
+
+		if n == nil {
+			lis = append(lis, Change{Kind: KindWire, Path: pat, Text: "rpc removed"})
+			continue
+		}
+
+		if o.IsStreamingClient() != n.IsStreamingClient() || o.IsStreamingServer() != n.IsStreamingServer() {
+			lis = append(lis, Change{Kind: KindWire, Path: pat, Text: "streaming changed"})
+		}
+		if o.Input().FullName() != n.Input().FullName() {
+			lis = append(lis, Change{Kind: KindSource, Path: pat, Text: fmt.Sprintf("input type changed from %s to %s", o.Input().FullName(), n.Input().FullName())})
+		}
+		if o.Output().FullName() != n.Output().FullName() {
+			lis = append(lis, Change{Kind: KindSource, Path: pat, Text: fmt.Sprintf("output type changed from %s to %s", o.Output().FullName(), n.Output().FullName())})
+		}
+	}
+
+	return lis
+}
+
+func messages(old protoreflect.MessageDescriptor, new protoreflect.MessageDescriptor) []Change {
+	pat := string(old.FullName())
+
+	if new == nil {
+		return []Change{{Kind: KindSource, Path: pat, Text: "message removed"}}
+	}
+
+	var lis []Change
+
+	for i := 0; i < old.Fields().Len(); i++ {
+		o := old.Fields().Get(i)
+		n := new.Fields().ByNumber(o.Number())
+
+		if n == nil {
+			r := new.Fields().ByName(o.Name())
+			if r != nil {
+				lis = append(lis, Change{Kind: KindWire, Path: pat, Text: fmt.Sprintf("field %q renumbered from %d to %d", o.Name(), o.Number(), r.Number())})
+			} else {
+				lis = append(lis, Change{Kind: KindWire, Path: pat, Text: fmt.Sprintf("field %d %q removed", o.Number(), o.Name())})
+			}
+
+			continue
+		}
+
+		lis = append(lis, fields(pat, o, n)...)
+	}
+
+	return lis
+}
+
+func fields(pat string, old protoreflect.FieldDescriptor, new protoreflect.FieldDescriptor) []Change {
+	var lis []Change
+
+	if old.Name() != new.Name() {
+		lis = append(lis, Change{Kind: KindSource, Path: pat, Text: fmt.Sprintf("field %d renamed from %q to %q", old.Number(), old.Name(), new.Name())})
+	}
+
+	if old.IsList() != new.IsList() || old.IsMap() != new.IsMap() {
+		lis = append(lis, Change{Kind: KindWire, Path: pat, Text: fmt.Sprintf("field %d %q changed cardinality", old.Number(), old.Name())})
+		return lis
+	}
+
+	if old.Kind() != new.Kind() {
+		kin := KindWire
+		if same(old.Kind(), new.Kind()) {
+			kin = KindSource
+		}
+
+		lis = append(lis, Change{Kind: kin, Path: pat, Text: fmt.Sprintf("field %d %q changed type from %s to %s", old.Number(), old.Name(), old.Kind(), new.Kind())})
+		return lis
+	}
+
+	if old.Message() != nil && old.Message().FullName() != new.Message().FullName() {
+		lis = append(lis, Change{Kind: KindSource, Path: pat, Text: fmt.Sprintf("field %d %q changed type from %s to %s", old.Number(), old.Name(), old.Message().FullName(), new.Message().FullName())})
+	}
+	if old.Enum() != nil && old.Enum().FullName() != new.Enum().FullName() {
+		lis = append(lis, Change{Kind: KindSource, Path: pat, Text: fmt.Sprintf("field %d %q changed type from %s to %s", old.Number(), old.Name(), old.Enum().FullName(), new.Enum().FullName())})
+	}
+
+	return lis
+}
+
+func enums(old protoreflect.EnumDescriptor, new protoreflect.EnumDescriptor) []Change {
+	pat := string(old.FullName())
+
+	if new == nil {
+		return []Change{{Kind: KindSource, Path: pat, Text: "enum removed"}}
+	}
+
+	var lis []Change
+
+	for i := 0; i < old.Values().Len(); i++ {
+		o := old.Values().Get(i)
+		n := new.Values().ByNumber(o.Number())
+
+		if n == nil {
+			lis = append(lis, Change{Kind: KindWire, Path: pat, Text: fmt.Sprintf("value %d %q removed", o.Number(), o.Name())})
+			continue
+		}
+
+		if o.Name() != n.Name() {
+			lis = append(lis, Change{Kind: KindSource, Path: pat, Text: fmt.Sprintf("value %d renamed from %q to %q", o.Number(), o.Name(), n.Name())})
+		}
+	}
+
+	return lis
+}
+
+func same(a protoreflect.Kind, b protoreflect.Kind) bool {
+	for _, g := range compatible {
+		var x, y bool
+		for _, k := range g {
+			x = x || k == a
+			y = y || k == b
+		}
+
+		if x && y {
+			return true
+		}
+	}
+
+	return false
+}
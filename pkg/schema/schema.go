@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/venturemark/cfm/pkg/compat"
+)
+
+const (
+	// KindSource is the kind of changes breaking the generated code, while
+	// messages remain compatible on the wire, e.g. renamed fields.
+	KindSource = "source"
+	// KindWire is the kind of changes breaking the communication between
+	// clients and servers built from different versions, e.g. removed fields.
+	KindWire = "wire"
+)
+
+// Change is a single breaking change between two versions of the protobuf
+// definitions.
+type Change struct {
+	// Kind is either KindSource or KindWire.
+	Kind string
+	// Path is the full name of the changed element, e.g. venture.CreateI.Obj
+	// or venture.API/Create.
+	Path string
+	// Text describes the change in human readable form.
+	Text string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%-6s  %s  %s", c.Kind, c.Path, c.Text)
+}
+
+// Diff returns all breaking changes between the versions old and new, sorted by
+// kind and path. Elements are matched by their full names, fields and enum
+// values additionally by their numbers.
+func Diff(old compat.Version, new compat.Version) []Change {
+	var lis []Change
+
+	o := index(old)
+	n := index(new)
+
+	for _, k := range sorted(o.services) {
+		lis = append(lis, services(o.services[k], n.services[k])...)
+	}
+
+	for _, k := range sorted(o.messages) {
+		lis = append(lis, messages(o.messages[k], n.messages[k])...)
+	}
+
+	for _, k := range sorted(o.enums) {
+		lis = append(lis, enums(o.enums[k], n.enums[k])...)
+	}
+
+	sort.SliceStable(lis, func(i, j int) bool {
+		if lis[i].Kind != lis[j].Kind {
+			return lis[i].Kind == KindWire
+		}
+
+		return lis[i].Path < lis[j].Path
+	})
+
+	return lis
+}
+
+// Filter returns all changes of the given kind.
+func Filter(lis []Change, kin string) []Change {
+	var fil []Change
+
+	for _, c := range lis {
+		if c.Kind == kin {
+			fil = append(fil, c)
+		}
+	}
+
+	return fil
+}
+
+type descriptors struct {
+	enums    map[string]protoreflect.EnumDescriptor
+	messages map[string]protoreflect.MessageDescriptor
+	services map[string]protoreflect.ServiceDescriptor
+}
+
+func index(ver compat.Version) descriptors {
+	d := descriptors{
+		enums:    map[string]protoreflect.EnumDescriptor{},
+		messages: map[string]protoreflect.MessageDescriptor{},
+		services: map[string]protoreflect.ServiceDescriptor{},
+	}
+
+	var msg func(m protoreflect.MessageDescriptor)
+	msg = func(m protoreflect.MessageDescriptor) {
+		if m.IsMapEntry() {
+			return
+		}
+
+		d.messages[string(m.FullName())] = m
+
+		for i := 0; i < m.Messages().Len(); i++ {
+			msg(m.Messages().Get(i))
+		}
+		for i := 0; i < m.Enums().Len(); i++ {
+			e := m.Enums().Get(i)
+			d.enums[string(e.FullName())] = e
+		}
+	}
+
+	for _, f := range ver.Files() {
+		for i := 0; i < f.Services().Len(); i++ {
+			s := f.Services().Get(i)
+			d.services[string(s.FullName())] = s
+		}
+		for i := 0; i < f.Messages().Len(); i++ {
+			msg(f.Messages().Get(i))
+		}
+		for i := 0; i < f.Enums().Len(); i++ {
+			e := f.Enums().Get(i)
+			d.enums[string(e.FullName())] = e
+		}
+	}
+
+	return d
+}
+
+func sorted[T any](m map[string]T) []string {
+	var key []string
+	for k := range m {
+		key = append(key, k)
+	}
+
+	sort.Strings(key)
+
+	return key
+}
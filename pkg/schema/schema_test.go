@@ -0,0 +1,181 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/venturemark/cfm/pkg/compat"
+)
+
+func Test_Schema_Diff(t *testing.T) {
+	testCases := []struct {
+		mod func(f *descriptorpb.FileDescriptorProto)
+		cha []Change
+	}{
+		// Case 0 ensures that identical versions do not differ.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {},
+			cha: nil,
+		},
+		// Case 1 ensures that removing a field breaks the wire.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {
+				m := f.MessageType[0]
+				m.Field = append(m.Field[:1], m.Field[2:]...)
+			},
+			cha: []Change{
+				{Kind: KindWire, Path: "venture.CreateI", Text: `field 2 "name" removed`},
+			},
+		},
+		// Case 2 ensures that renumbering a field breaks the wire.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[1].Number = proto.Int32(4)
+			},
+			cha: []Change{
+				{Kind: KindWire, Path: "venture.CreateI", Text: `field "name" renumbered from 2 to 4`},
+			},
+		},
+		// Case 3 ensures that changing the type of a field to a type encoded
+		// differently breaks the wire.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[2].Type = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+			},
+			cha: []Change{
+				{Kind: KindWire, Path: "venture.CreateI", Text: `field 3 "size" changed type from int64 to string`},
+			},
+		},
+		// Case 4 ensures that changing the type of a field to a type encoded
+		// the same way only breaks the source.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[2].Type = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
+			},
+			cha: []Change{
+				{Kind: KindSource, Path: "venture.CreateI", Text: `field 3 "size" changed type from int64 to int32`},
+			},
+		},
+		// Case 5 ensures that renaming a field only breaks the source.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {
+				f.MessageType[0].Field[1].Name = proto.String("title")
+				f.MessageType[0].Field[1].JsonName = proto.String("title")
+			},
+			cha: []Change{
+				{Kind: KindSource, Path: "venture.CreateI", Text: `field 2 renamed from "name" to "title"`},
+			},
+		},
+		// Case 6 ensures that removing an RPC breaks the wire.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {
+				s := f.Service[0]
+				s.Method = s.Method[:1]
+			},
+			cha: []Change{
+				{Kind: KindWire, Path: "venture.API/Delete", Text: "rpc removed"},
+			},
+		},
+		// Case 7 ensures that removing an enum value breaks the wire.
+		{
+			mod: func(f *descriptorpb.FileDescriptorProto) {
+				e := f.EnumType[0]
+				e.Value = e.Value[:1]
+			},
+			cha: []Change{
+				{Kind: KindWire, Path: "venture.Stat", Text: `value 1 "ARCHIVED" removed`},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			old := schemaFile()
+
+			new := proto.Clone(old).(*descriptorpb.FileDescriptorProto)
+			tc.mod(new)
+
+			cha := Diff(schemaVersion(t, old), schemaVersion(t, new))
+			if !reflect.DeepEqual(cha, tc.cha) {
+				t.Fatalf("expected %v got %v", tc.cha, cha)
+			}
+		})
+	}
+}
+
+// schemaFile returns the descriptor of a minimal apigengo proto file, which
+// the test cases modify in order to describe a new version.
+func schemaFile() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("pbf/venture/api.proto"),
+		Package: proto.String("venture"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("CreateI"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					schemaField("obj", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					schemaField("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					schemaField("size", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+				},
+			},
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name: proto.String("Stat"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: proto.String("ACTIVE"), Number: proto.Int32(0)},
+					{Name: proto.String("ARCHIVED"), Number: proto.Int32(1)},
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("API"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("Create"), InputType: proto.String(".venture.CreateI"), OutputType: proto.String(".venture.CreateI")},
+					{Name: proto.String("Delete"), InputType: proto.String(".venture.CreateI"), OutputType: proto.String(".venture.CreateI")},
+				},
+			},
+		},
+	}
+}
+
+func schemaField(nam string, num int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(nam),
+		JsonName: proto.String(nam),
+		Number:   proto.Int32(num),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     typ.Enum(),
+	}
+}
+
+// schemaVersion loads the given file descriptor the way serialized versions
+// are loaded by the compatibility matrix.
+func schemaVersion(t *testing.T, fil *descriptorpb.FileDescriptorProto) compat.Version {
+	byt, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fil}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pat := filepath.Join(t.TempDir(), "v0.0.0.pb")
+
+	err = os.WriteFile(pat, byt, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ver, err := compat.Load(pat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ver
+}
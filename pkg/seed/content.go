@@ -0,0 +1,91 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+var (
+	first = []string{"ada", "alan", "barbara", "dennis", "edsger", "frances", "grace", "john", "ken", "linus", "margaret", "niklaus", "radia", "rob", "shafi", "tim"}
+	heads = []string{"Weekly Update", "Launch Recap", "Hiring", "Fundraising", "Roadmap", "Customer Feedback", "Metrics Review", "Retrospective", "Milestone Reached", "Open Questions"}
+	noun  = []string{"Analytics", "Cloud", "Commerce", "Data", "Devices", "Energy", "Foods", "Health", "Labs", "Logistics", "Media", "Robotics", "Security", "Systems", "Ventures"}
+	pref  = []string{"Acme", "Blue", "Bright", "Clear", "Deep", "Green", "Iron", "North", "Open", "Quantum", "Red", "Silver", "Solar", "Swift", "True"}
+	teams = []string{"Engineering", "Design", "Marketing", "Sales", "Operations", "Research", "Support", "Finance", "Product", "Growth"}
+	words = []string{"we", "shipped", "the", "new", "onboarding", "flow", "and", "customers", "love", "it", "revenue", "grew", "by", "ten", "percent", "next", "week", "focus", "is", "on", "retention", "pricing", "experiments", "ran", "well", "team", "hired", "two", "engineers", "blocked", "infrastructure", "migration", "done", "ahead", "of", "schedule"}
+)
+
+// content generates varied names and texts from a seeded source of
+// randomness. The same seed always generates the same sequence of content.
+type content struct {
+	ran *rand.Rand
+}
+
+func newContent(see int64) *content {
+	return &content{
+		ran: rand.New(rand.NewSource(see)),
+	}
+}
+
+func (c *content) pick(lis []string) string {
+	return lis[c.ran.Intn(len(lis))]
+}
+
+func (c *content) head() string {
+	return c.pick(heads)
+}
+
+// mail returns the unique mail address of the i-th user seeded with see.
+func (c *content) mail(nam string, see int64, i int) string {
+	return fmt.Sprintf("%s.%d.%d@seed.venturemark.co", nam, see, i)
+}
+
+func (c *content) name() string {
+	return c.pick(first)
+}
+
+func (c *content) text(min int, max int) string {
+	n := min + c.ran.Intn(max-min+1)
+
+	var lis []string
+	for i := 0; i < n; i++ {
+		lis = append(lis, c.pick(words))
+	}
+
+	s := strings.Join(lis, " ")
+
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// timeline returns the name of the timeline with the given index. The index
+// keeps the names unique within a venture, no matter how many timelines get
+// seeded.
+func (c *content) timeline(ind int) string {
+	return fmt.Sprintf("%s %s %d", c.pick(teams), c.pick(heads), ind+1)
+}
+
+func (c *content) venture() string {
+	return c.pick(pref) + " " + c.pick(noun)
+}
+
+// chance returns true with the probability of 1 in n.
+func (c *content) chance(n int) bool {
+	return c.ran.Intn(n) == 0
+}
+
+// others returns n distinct indices between 0 and max, excluding exc.
+func (c *content) others(n int, max int, exc int) []int {
+	var lis []int
+	for _, i := range c.ran.Perm(max) {
+		if i == exc {
+			continue
+		}
+		if len(lis) == n {
+			break
+		}
+
+		lis = append(lis, i)
+	}
+
+	return lis
+}
@@ -0,0 +1,40 @@
+package seed
+
+import (
+	"errors"
+
+	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var invalidConfigError = &tracer.Error{
+	Kind: "invalidConfigError",
+}
+
+func IsInvalidConfig(err error) bool {
+	return errors.Is(err, invalidConfigError)
+}
+
+var invalidManifestError = &tracer.Error{
+	Kind: "invalidManifestError",
+}
+
+func IsInvalidManifest(err error) bool {
+	return errors.Is(err, invalidManifestError)
+}
+
+var rollbackError = &tracer.Error{
+	Kind: "rollbackError",
+}
+
+func IsRollback(err error) bool {
+	return errors.Is(err, rollbackError)
+}
+
+// isAbsent checks whether err indicates that a deleted resource does not exist
+// anymore, e.g. because it got torn down before.
+func isAbsent(err error) bool {
+	c := status.Code(tracer.Cause(err))
+	return err != nil && (c == codes.NotFound || c == codes.PermissionDenied)
+}
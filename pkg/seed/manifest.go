@@ -0,0 +1,58 @@
+package seed
+
+// Manifest describes all resources created by Seed.Create. The manifest
+// carries the credentials of every seeded user, so that the data can be
+// inspected using the seeded identities and torn down using Seed.Delete.
+// Running Seed.Create with the same configuration recreates the same content,
+// while the IDs are assigned by the apiserver.
+type Manifest struct {
+	Config Config `json:"config"`
+	Users  []User `json:"users"`
+}
+
+type User struct {
+	ID       string    `json:"id"`
+	Mail     string    `json:"mail"`
+	Name     string    `json:"name"`
+	Token    string    `json:"token"`
+	Ventures []Venture `json:"ventures,omitempty"`
+}
+
+type Venture struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Invites   []Invite   `json:"invites,omitempty"`
+	Roles     []Role     `json:"roles,omitempty"`
+	Timelines []Timeline `json:"timelines,omitempty"`
+}
+
+type Invite struct {
+	ID   string `json:"id"`
+	Mail string `json:"mail"`
+}
+
+type Role struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+}
+
+type Timeline struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Stat    string   `json:"stat"`
+	Updates []Update `json:"updates,omitempty"`
+}
+
+type Update struct {
+	ID       string    `json:"id"`
+	Head     string    `json:"head"`
+	Text     string    `json:"text"`
+	Messages []Message `json:"messages,omitempty"`
+}
+
+type Message struct {
+	ID   string `json:"id"`
+	Reid string `json:"reid,omitempty"`
+	Text string `json:"text"`
+}
@@ -0,0 +1,478 @@
+package seed
+
+import (
+	"context"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+type Config struct {
+	// Address is the address of the apiserver. Defaults to the default address
+	// of client.Config.
+	Address string `json:"address,omitempty"`
+	// Invites is the number of invites created per venture.
+	Invites int `json:"invites"`
+	// Messages is the number of messages created per update.
+	Messages int `json:"messages"`
+	// Roles is the number of member roles created per venture, each granting
+	// another seeded user access to the venture. Roles must be lower than
+	// Users.
+	Roles int `json:"roles"`
+	// Seed determines the generated content. The same seed always generates the
+	// same content.
+	Seed int64 `json:"seed"`
+	// Timelines is the number of timelines created per venture.
+	Timelines int `json:"timelines"`
+	// Updates is the number of text updates created per timeline.
+	Updates int `json:"updates"`
+	// Users is the number of users created.
+	Users int `json:"users"`
+	// Ventures is the number of ventures created per user.
+	Ventures int `json:"ventures"`
+}
+
+// Seed creates realistic data for demos and manual QA using the public API.
+// Usage looks like the following.
+//
+//	man, err := see.Create(ctx)
+//	...
+//	err = see.Delete(ctx, man)
+type Seed struct {
+	config Config
+}
+
+func New(c Config) (*Seed, error) {
+	if c.Users < 1 {
+		return nil, tracer.Maskf(invalidConfigError, "%T.Users must be at least 1", c)
+	}
+	if c.Roles < 0 || c.Roles >= c.Users {
+		return nil, tracer.Maskf(invalidConfigError, "%T.Roles must be lower than %T.Users", c, c)
+	}
+	if c.Invites < 0 || c.Messages < 0 || c.Timelines < 0 || c.Updates < 0 || c.Ventures < 0 {
+		return nil, tracer.Maskf(invalidConfigError, "%T counts must not be negative", c)
+	}
+
+	s := &Seed{
+		config: c,
+	}
+
+	return s, nil
+}
+
+// Create creates all users first, and then the ventures of every user
+// including their roles, invites, timelines, updates and messages. Every
+// resource is created by the user owning the venture. Some timelines get
+// archived once their content got created. Resources created before a failure
+// are deleted again, so that a failed Create leaves nothing behind.
+func (s *Seed) Create(ctx context.Context) (*Manifest, error) {
+	man, err := s.create(ctx)
+	if err != nil {
+		// The context of the failed seed may be cancelled already, which must
+		// not prevent the rollback.
+		del := s.Delete(context.Background(), man)
+		if del != nil {
+			return nil, tracer.Maskf(rollbackError, "seeded data must be deleted after failed seed: %s: %s", del, err)
+		}
+
+		return nil, tracer.Mask(err)
+	}
+
+	return man, nil
+}
+
+// create creates the data described by Create. On failure, create returns the
+// manifest of all resources created so far along with the error.
+func (s *Seed) create(ctx context.Context) (*Manifest, error) {
+	var err error
+
+	con := newContent(s.config.Seed)
+	man := &Manifest{Config: s.config}
+
+	var cli []*client.Client
+	defer func() {
+		for _, c := range cli {
+			c.Close()
+		}
+	}()
+
+	for i := 0; i < s.config.Users; i++ {
+		nam := con.name()
+		mai := con.mail(nam, s.config.Seed, i)
+		cre := oauth.NewInsecure(mai)
+
+		var c *client.Client
+		{
+			c, err = s.client(cre)
+			if err != nil {
+				return man, tracer.Mask(err)
+			}
+
+			cli = append(cli, c)
+		}
+
+		var usi string
+		{
+			i := &user.CreateI{
+				Obj: []*user.CreateI_Obj{
+					{
+						Property: &user.CreateI_Obj_Property{
+							Desc: con.text(4, 8),
+							Mail: mai,
+							Name: nam,
+						},
+					},
+				},
+			}
+
+			o, err := c.User().Create(ctx, i)
+			if err != nil {
+				return man, tracer.Mask(err)
+			}
+
+			usi = o.Obj[0].Metadata["user.venturemark.co/id"]
+		}
+
+		man.Users = append(man.Users, User{
+			ID:    usi,
+			Mail:  mai,
+			Name:  nam,
+			Token: cre.Token(),
+		})
+	}
+
+	for i := range man.Users {
+		for j := 0; j < s.config.Ventures; j++ {
+			v, err := s.venture(ctx, con, cli[i], man.Users, i)
+			if v.ID != "" {
+				man.Users[i].Ventures = append(man.Users[i].Ventures, v)
+			}
+			if err != nil {
+				return man, tracer.Mask(err)
+			}
+		}
+	}
+
+	return man, nil
+}
+
+// Delete deletes all ventures of the given manifest, which deletes their
+// timelines, updates, messages, invites and roles in turn, and then all users.
+// Resources that do not exist anymore are ignored, so that Delete can be
+// retried.
+func (s *Seed) Delete(ctx context.Context, man *Manifest) error {
+	for _, u := range man.Users {
+		if u.ID == "" || u.Mail == "" {
+			return tracer.Maskf(invalidManifestError, "user must have ID and mail")
+		}
+	}
+
+	var cli []*client.Client
+	defer func() {
+		for _, c := range cli {
+			c.Close()
+		}
+	}()
+
+	for _, u := range man.Users {
+		c, err := s.client(oauth.NewInsecure(u.Mail))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		cli = append(cli, c)
+	}
+
+	for j, u := range man.Users {
+		for _, v := range u.Ventures {
+			i := &venture.DeleteI{
+				Obj: []*venture.DeleteI_Obj{
+					{
+						Metadata: map[string]string{
+							"venture.venturemark.co/id": v.ID,
+						},
+					},
+				},
+			}
+
+			_, err := cli[j].Venture().Delete(ctx, i)
+			if isAbsent(err) {
+				continue
+			} else if err != nil {
+				return tracer.Mask(err)
+			}
+		}
+	}
+
+	for j, u := range man.Users {
+		i := &user.DeleteI{
+			Obj: []*user.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"user.venturemark.co/id": u.ID,
+					},
+				},
+			},
+		}
+
+		_, err := cli[j].User().Delete(ctx, i)
+		if isAbsent(err) {
+			continue
+		} else if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Seed) client(cre *oauth.Insecure) (*client.Client, error) {
+	c := client.Config{
+		Address:     s.config.Address,
+		Credentials: cre,
+	}
+
+	cli, err := client.New(c)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	return cli, nil
+}
+
+// venture creates a single venture owned by the own-th user of use, including
+// all of its descendants.
+func (s *Seed) venture(ctx context.Context, con *content, cli *client.Client, use []User, own int) (Venture, error) {
+	v := Venture{
+		Name: con.venture(),
+	}
+
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Desc: con.text(6, 12),
+						Link: []*venture.CreateI_Obj_Property_Link{
+							{
+								Addr: "https://example.com",
+								Text: v.Name,
+							},
+						},
+						Name: v.Name,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(ctx, i)
+		if err != nil {
+			return Venture{}, tracer.Mask(err)
+		}
+
+		v.ID = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	for _, j := range con.others(s.config.Roles, len(use), own) {
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    use[j].ID,
+						"venture.venturemark.co/id":    v.ID,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Create(ctx, i)
+		if err != nil {
+			return v, tracer.Mask(err)
+		}
+
+		v.Roles = append(v.Roles, Role{
+			ID:      o.Obj[0].Metadata["role.venturemark.co/id"],
+			Kind:    "member",
+			Subject: use[j].ID,
+		})
+	}
+
+	for j := 0; j < s.config.Invites; j++ {
+		mai := con.mail(con.name(), s.config.Seed, len(use)+j)
+
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": v.ID,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: mai,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Invite().Create(ctx, i)
+		if err != nil {
+			return v, tracer.Mask(err)
+		}
+
+		v.Invites = append(v.Invites, Invite{
+			ID:   o.Obj[0].Metadata["invite.venturemark.co/id"],
+			Mail: mai,
+		})
+	}
+
+	for j := 0; j < s.config.Timelines; j++ {
+		t, err := s.timeline(ctx, con, cli, v.ID, j)
+		if err != nil {
+			return v, tracer.Mask(err)
+		}
+
+		v.Timelines = append(v.Timelines, t)
+	}
+
+	return v, nil
+}
+
+// timeline creates a single timeline within the venture vei, including its
+// updates and messages. Every fourth timeline gets archived on average.
+func (s *Seed) timeline(ctx context.Context, con *content, cli *client.Client, vei string, ind int) (Timeline, error) {
+	t := Timeline{
+		Name: con.timeline(ind),
+		Stat: "active",
+	}
+
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Desc: con.text(4, 10),
+						Name: t.Name,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(ctx, i)
+		if err != nil {
+			return Timeline{}, tracer.Mask(err)
+		}
+
+		t.ID = o.Obj[0].Metadata["timeline.venturemark.co/id"]
+	}
+
+	for j := 0; j < s.config.Updates; j++ {
+		u := Update{
+			Head: con.head(),
+			Text: con.text(10, 30),
+		}
+
+		{
+			i := &texupd.CreateI{
+				Obj: []*texupd.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": t.ID,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &texupd.CreateI_Obj_Property{
+							Head: u.Head,
+							Text: u.Text,
+						},
+					},
+				},
+			}
+
+			o, err := cli.TexUpd().Create(ctx, i)
+			if err != nil {
+				return Timeline{}, tracer.Mask(err)
+			}
+
+			u.ID = o.Obj[0].Metadata["update.venturemark.co/id"]
+		}
+
+		for k := 0; k < s.config.Messages; k++ {
+			m := Message{
+				Text: con.text(3, 12),
+			}
+
+			if len(u.Messages) != 0 && con.chance(3) {
+				m.Reid = u.Messages[con.ran.Intn(len(u.Messages))].ID
+			}
+
+			i := &message.CreateI{
+				Obj: []*message.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": t.ID,
+							"update.venturemark.co/id":   u.ID,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &message.CreateI_Obj_Property{
+							Reid: m.Reid,
+							Text: m.Text,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Message().Create(ctx, i)
+			if err != nil {
+				return Timeline{}, tracer.Mask(err)
+			}
+
+			m.ID = o.Obj[0].Metadata["message.venturemark.co/id"]
+
+			u.Messages = append(u.Messages, m)
+		}
+
+		t.Updates = append(t.Updates, u)
+	}
+
+	if con.chance(4) {
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": t.ID,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{
+							Ope: "replace",
+							Pat: "/obj/property/stat",
+							Val: to.StringP("archived"),
+						},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Update(ctx, i)
+		if err != nil {
+			return Timeline{}, tracer.Mask(err)
+		}
+
+		t.Stat = "archived"
+	}
+
+	return t, nil
+}
@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/xh3b4sd/tracer"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// file is a span exporter writing every span as JSON line to the underlying
+// writer.
+type file struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// line is the JSON representation of a single span.
+type line struct {
+	Trace      string            `json:"trace"`
+	Span       string            `json:"span"`
+	Parent     string            `json:"parent,omitempty"`
+	Name       string            `json:"name"`
+	Kind       string            `json:"kind"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Status     string            `json:"status"`
+	Message    string            `json:"message,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+func newFile(w io.Writer) *file {
+	return &file{
+		writer: w,
+	}
+}
+
+func (f *file) ExportSpans(ctx context.Context, spa []sdktrace.ReadOnlySpan) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	enc := json.NewEncoder(f.writer)
+
+	for _, s := range spa {
+		l := line{
+			Trace:   s.SpanContext().TraceID().String(),
+			Span:    s.SpanContext().SpanID().String(),
+			Name:    s.Name(),
+			Kind:    s.SpanKind().String(),
+			Start:   s.StartTime(),
+			End:     s.EndTime(),
+			Status:  s.Status().Code.String(),
+			Message: s.Status().Description,
+		}
+
+		if s.Parent().IsValid() {
+			l.Parent = s.Parent().SpanID().String()
+		}
+
+		for _, a := range s.Attributes() {
+			if l.Attributes == nil {
+				l.Attributes = map[string]string{}
+			}
+
+			l.Attributes[string(a.Key)] = a.Value.Emit()
+		}
+
+		err := enc.Encode(l)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (f *file) Shutdown(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,122 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+
+	"github.com/xh3b4sd/tracer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Config struct {
+	// Endpoint is the optional address of an OTLP collector accepting traces
+	// via gRPC, e.g. 127.0.0.1:4317.
+	Endpoint string
+	// File is the optional path of the file spans are written to as JSON
+	// lines. The file exporter works offline, e.g. in CI.
+	File string
+	// Service is the service name reported with every span. Defaults to cfm.
+	Service string
+}
+
+// Telemetry emits one trace per conformance test. Spans are only recorded if
+// at least one exporter is configured. Usage looks like the following.
+//
+//	spa := tel.Start(t.Name())
+//	defer spa.End()
+//
+//	c := client.Config{
+//		Span: spa,
+//	}
+type Telemetry struct {
+	export   bool
+	file     *os.File
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+func New(c Config) (*Telemetry, error) {
+	if c.Service == "" {
+		c.Service = "cfm"
+	}
+
+	var err error
+
+	var opt []sdktrace.TracerProviderOption
+	{
+		r := resource.NewSchemaless(
+			attribute.String("service.name", c.Service),
+		)
+
+		opt = append(opt, sdktrace.WithResource(r))
+	}
+
+	if c.Endpoint != "" {
+		e, err := otlptracegrpc.New(
+			context.Background(),
+			otlptracegrpc.WithEndpoint(c.Endpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		opt = append(opt, sdktrace.WithBatcher(e))
+	}
+
+	var fil *os.File
+	if c.File != "" {
+		fil, err = os.Create(c.File)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+
+		opt = append(opt, sdktrace.WithSyncer(newFile(fil)))
+	}
+
+	var pro *sdktrace.TracerProvider
+	{
+		pro = sdktrace.NewTracerProvider(opt...)
+	}
+
+	t := &Telemetry{
+		export:   c.Endpoint != "" || c.File != "",
+		file:     fil,
+		provider: pro,
+		tracer:   pro.Tracer("github.com/venturemark/cfm"),
+	}
+
+	return t, nil
+}
+
+// Shutdown flushes all pending spans to the configured exporters and releases
+// their resources. Note that the provider fails to shut down without any span
+// processor, which is why it is only shut down if any exporter is configured.
+func (t *Telemetry) Shutdown() error {
+	if t.export {
+		err := t.provider.Shutdown(context.Background())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	if t.file != nil {
+		err := t.file.Close()
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// Start begins the root span of a new trace named nam, e.g. the name of a
+// conformance test. The caller must end the returned span.
+func (t *Telemetry) Start(nam string) trace.Span {
+	_, s := t.tracer.Start(context.Background(), nam, trace.WithNewRoot())
+	return s
+}
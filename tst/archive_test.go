@@ -0,0 +1,329 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+
+	"github.com/venturemark/cfm/pkg/archive"
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/compat"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/seed"
+)
+
+// Test_Archive_001 ensures that a venture exported by its owner can be
+// imported under a new owner, such that exporting the imported venture yields
+// the same content with remapped IDs.
+func Test_Archive_001(t *testing.T) {
+	var err error
+
+	var cl2 *client.Client
+	{
+		cl2 = newClient(t, oauth.NewInsecureTwo())
+
+		err = purge(cl2)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var man *seed.Manifest
+	{
+		c := seed.Config{
+			Invites:   2,
+			Messages:  3,
+			Roles:     1,
+			Seed:      7,
+			Timelines: 3,
+			Updates:   2,
+			Users:     2,
+			Ventures:  1,
+		}
+
+		see, err := seed.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		man, err = see.Create(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The seeded data is created using clients of the seed package, which
+		// do not delete their resources in non destructive mode.
+		t.Cleanup(func() {
+			err := see.Delete(context.Background(), man)
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	cl1 := newClient(t, oauth.NewInsecure(man.Users[0].Mail))
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "Jane",
+						Mail: "two@user.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ar1 *archive.Archiver
+	{
+		c := archive.Config{
+			Client: cl1,
+		}
+
+		ar1, err = archive.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ar2 *archive.Archiver
+	{
+		c := archive.Config{
+			Client: cl2,
+		}
+
+		ar2, err = archive.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ex1 *archive.Archive
+	{
+		ex1, err = ar1.Export(context.Background(), man.Users[0].Ventures[0].ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ex1.Venture.Roles) != 2 {
+			t.Fatal("there must be two roles")
+		}
+		if len(ex1.Venture.Timelines) != 3 {
+			t.Fatal("there must be three timelines")
+		}
+	}
+
+	var ids map[string]string
+	{
+		ids, err = ar2.Import(context.Background(), ex1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ids[ex1.Venture.ID] == "" || ids[ex1.Venture.ID] == ex1.Venture.ID {
+			t.Fatal("venture id must be remapped")
+		}
+	}
+
+	var ex2 *archive.Archive
+	{
+		ex2, err = ar2.Export(context.Background(), ids[ex1.Venture.ID])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ex2.Owner != ids[ex1.Owner] {
+			t.Fatal("owner must be remapped")
+		}
+
+		// The member role of the seeded subject is only recreated with
+		// archive.Config.Members, which leaves the new owner only.
+		if len(ex2.Venture.Roles) != 1 {
+			t.Fatal("there must be one role")
+		}
+		if ex2.Venture.Roles[0].Subject != ex2.Owner {
+			t.Fatal("subject must be the new owner")
+		}
+	}
+
+	{
+		if archiveContent(t, ex1) != archiveContent(t, ex2) {
+			t.Fatal("content must match across archives")
+		}
+	}
+}
+
+// archiveContent returns the JSON representation of arc without any of the
+// IDs and roles bound to the environment the archive got exported from.
+// Timelines and invites are sorted, since their order is not guaranteed by
+// the Search APIs. Note that arc gets modified.
+func archiveContent(t *testing.T, arc *archive.Archive) string {
+	arc.Owner = ""
+	arc.Venture.ID = ""
+	arc.Venture.Roles = nil
+
+	for i := range arc.Venture.Invites {
+		arc.Venture.Invites[i].ID = ""
+	}
+
+	for i, l := range arc.Venture.Timelines {
+		arc.Venture.Timelines[i].ID = ""
+		arc.Venture.Timelines[i].Roles = nil
+
+		for j, u := range l.Updates {
+			l.Updates[j].ID = ""
+
+			for k := range u.Messages {
+				u.Messages[k].ID = ""
+				u.Messages[k].Reid = ""
+			}
+		}
+	}
+
+	sort.Slice(arc.Venture.Invites, func(i, j int) bool {
+		return arc.Venture.Invites[i].Mail < arc.Venture.Invites[j].Mail
+	})
+
+	sort.Slice(arc.Venture.Timelines, func(i, j int) bool {
+		return archiveJSON(t, arc.Venture.Timelines[i]) < archiveJSON(t, arc.Venture.Timelines[j])
+	})
+
+	return archiveJSON(t, arc)
+}
+
+func archiveJSON(t *testing.T, v interface{}) string {
+	byt, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(byt)
+}
+
+// Test_Archive_002 ensures that archives exported with another apigengo
+// version are rejected, and that a failed import deletes the partially
+// imported venture again.
+func Test_Archive_002(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, oauth.NewInsecureTwo())
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var usi string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "Jane",
+						Mail: "two@user.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		usi = o.Obj[0].Metadata["user.venturemark.co/id"]
+	}
+
+	var arc *archive.Archiver
+	{
+		c := archive.Config{
+			Client: cli,
+		}
+
+		arc, err = archive.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		a := &archive.Archive{
+			Version: archive.Version,
+			Schema:  "v0.0.0",
+			Owner:   "1",
+			Venture: archive.Venture{
+				ID:   "2",
+				Name: "IBM",
+			},
+		}
+
+		_, err := arc.Import(context.Background(), a)
+		if !archive.IsInvalidArchive(err) {
+			t.Fatalf("expected %#v got %#v", "invalidArchiveError", err)
+		}
+	}
+
+	// Timeline names are unique within a venture, which is why the second
+	// timeline cannot be imported.
+	{
+		a := &archive.Archive{
+			Version: archive.Version,
+			Schema:  compat.Pinned,
+			Owner:   "1",
+			Venture: archive.Venture{
+				ID:   "2",
+				Name: "IBM",
+				Timelines: []archive.Timeline{
+					{ID: "3", Name: "Marketing Campaign"},
+					{ID: "4", Name: "Marketing Campaign"},
+				},
+			},
+		}
+
+		_, err := arc.Import(context.Background(), a)
+		if err == nil {
+			t.Fatal("error must not be empty")
+		}
+		if archive.IsRollback(err) {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"subject.venturemark.co/id": usi,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero ventures")
+		}
+	}
+}
@@ -0,0 +1,1125 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/update"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/keyspace"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// The tests in this file send batches of multiple objects within a single
+// request. The conformance rules verified here are as follows.
+//
+//     * Batches are atomic. Either all objects of a batch are applied or none
+//       is, in which case the Redis keyspace remains unchanged.
+//     * A batch containing an invalid object, or the same ID multiple times, is
+//       rejected with codes.InvalidArgument.
+//     * Objects of a batch may span multiple ventures, as long as the caller is
+//       permitted to act on all of them.
+//     * The response carries one object per request object, in order, such
+//       that o.Obj[i] describes the result of i.Obj[i].
+
+// Test_Batch_001 ensures that timelines spanning multiple ventures can be
+// created within a single batch and that results map back to their request
+// objects.
+func Test_Batch_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ve1 string
+	var ve2 string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "Apple",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 2 {
+			t.Fatal("there must be two ventures")
+		}
+
+		ve1 = o.Obj[0].Metadata["venture.venturemark.co/id"]
+		ve2 = o.Obj[1].Metadata["venture.venturemark.co/id"]
+
+		if ve1 == "" || ve2 == "" {
+			t.Fatal("id must not be empty")
+		}
+		if ve1 == ve2 {
+			t.Fatal("ids must be unique")
+		}
+	}
+
+	{
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one venture")
+		}
+		if o.Obj[0].Property.Name != "IBM" {
+			t.Fatal("o.Obj[0] must describe i.Obj[0]")
+		}
+	}
+
+	var tim *timeline.CreateI
+	{
+		tim = &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Alpha",
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve2,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Beta",
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Gamma",
+					},
+				},
+			},
+		}
+	}
+
+	var tii []string
+	{
+		o, err := cli.Timeline().Create(context.Background(), tim)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != len(tim.Obj) {
+			t.Fatalf("there must be %d timelines", len(tim.Obj))
+		}
+
+		for _, x := range o.Obj {
+			s, ok := x.Metadata["timeline.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+
+			tii = append(tii, s)
+		}
+	}
+
+	for j, x := range tim.Obj {
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": x.Metadata["venture.venturemark.co/id"],
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var nam string
+		for _, y := range o.Obj {
+			if y.Metadata["timeline.venturemark.co/id"] == tii[j] {
+				nam = y.Property.Name
+			}
+		}
+
+		if nam != x.Property.Name {
+			t.Fatalf("o.Obj[%d] must describe i.Obj[%d]", j, j)
+		}
+	}
+}
+
+// Test_Batch_002 ensures that timeline batches containing an invalid object are
+// rejected as a whole.
+func Test_Batch_002(t *testing.T) {
+	var err error
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ve1 string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ve1 = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	var ve2 string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "Apple",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ve2 = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	var bef []string
+	{
+		bef, err = keyspace.Search(cl1.Redigo())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Timeline names are unique within a venture. So the second object of the
+	// batch conflicts with the first one.
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Alpha",
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Alpha",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Timeline().Create(context.Background(), i)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+		}
+	}
+
+	// The last object of the batch is invalid, because timelines must have a
+	// name.
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Alpha",
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Timeline().Create(context.Background(), i)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+		}
+	}
+
+	// The first user is not a member of the second venture. So the second
+	// object of the batch must not be created, and neither must the first.
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Alpha",
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve2,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Beta",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Timeline().Create(context.Background(), i)
+		if err == nil {
+			t.Fatal("error must not be empty")
+		}
+	}
+
+	{
+		aft, err := keyspace.Search(cl1.Redigo())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(bef, aft) {
+			t.Fatalf("keyspace must not change: added %v removed %v", keyspace.Added(bef, aft), keyspace.Removed(bef, aft))
+		}
+	}
+
+	{
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Timeline().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must not be any timeline")
+		}
+	}
+}
+
+// Test_Batch_003 ensures that text update batches map results back to their
+// request objects and that delete batches containing duplicate IDs are
+// rejected as a whole.
+func Test_Batch_003(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tii = o.Obj[0].Metadata["timeline.venturemark.co/id"]
+	}
+
+	var txt []string
+	var upi []string
+	{
+		i := &texupd.CreateI{}
+		for _, s := range []string{"first", "second", "third"} {
+			i.Obj = append(i.Obj, &texupd.CreateI_Obj{
+				Metadata: map[string]string{
+					"timeline.venturemark.co/id": tii,
+					"venture.venturemark.co/id":  vei,
+				},
+				Property: &texupd.CreateI_Obj_Property{
+					Text: s,
+				},
+			})
+
+			txt = append(txt, s)
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != len(i.Obj) {
+			t.Fatalf("there must be %d updates", len(i.Obj))
+		}
+
+		for _, x := range o.Obj {
+			s, ok := x.Metadata["update.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+
+			upi = append(upi, s)
+		}
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 3 {
+			t.Fatal("there must be three updates")
+		}
+
+		for j := range upi {
+			var s string
+			for _, x := range o.Obj {
+				if x.Metadata["update.venturemark.co/id"] == upi[j] {
+					s = x.Property.Text
+				}
+			}
+
+			if s != txt[j] {
+				t.Fatalf("o.Obj[%d] must describe i.Obj[%d]", j, j)
+			}
+		}
+	}
+
+	{
+		i := &texupd.DeleteI{
+			Obj: []*texupd.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi[0],
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi[1],
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi[0],
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		_, err := cli.TexUpd().Delete(context.Background(), i)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+		}
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 3 {
+			t.Fatal("there must be three updates")
+		}
+	}
+
+	{
+		i := &texupd.DeleteI{
+			Obj: []*texupd.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi[2],
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi[0],
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != len(i.Obj) {
+			t.Fatalf("there must be %d results", len(i.Obj))
+		}
+
+		for j, x := range o.Obj {
+			s, ok := x.Metadata["update.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatalf("o.Obj[%d] status must be deleted", j)
+			}
+		}
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one update")
+		}
+		if o.Obj[0].Metadata["update.venturemark.co/id"] != upi[1] {
+			t.Fatal("the second update must remain")
+		}
+	}
+}
+
+// Test_Batch_004 ensures that message update batches containing an invalid
+// object are rejected as a whole and that valid batches are applied per object.
+func Test_Batch_004(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tii = o.Obj[0].Metadata["timeline.venturemark.co/id"]
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		upi = o.Obj[0].Metadata["update.venturemark.co/id"]
+	}
+
+	var me1 string
+	var me2 string
+	{
+		i := &message.CreateI{
+			Obj: []*message.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: "foo",
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: "bar",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 2 {
+			t.Fatal("there must be two messages")
+		}
+
+		me1 = o.Obj[0].Metadata["message.venturemark.co/id"]
+		me2 = o.Obj[1].Metadata["message.venturemark.co/id"]
+	}
+
+	// The second object of the batch refers to a message which does not exist.
+	{
+		i := &message.UpdateI{
+			Obj: []*message.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"message.venturemark.co/id":  me1,
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+						{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("never")},
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"message.venturemark.co/id":  "1",
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+						{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("never")},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Message().Update(context.Background(), i)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+		}
+	}
+
+	{
+		i := &message.UpdateI{
+			Obj: []*message.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"message.venturemark.co/id":  me2,
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+						{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("baz")},
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"message.venturemark.co/id":  me1,
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+						{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("foo")},
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Update(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != len(i.Obj) {
+			t.Fatalf("there must be %d results", len(i.Obj))
+		}
+
+		for j, x := range o.Obj {
+			if x.Metadata["message.venturemark.co/id"] != i.Obj[j].Metadata["message.venturemark.co/id"] {
+				t.Fatalf("o.Obj[%d] must describe i.Obj[%d]", j, j)
+			}
+
+			s, ok := x.Metadata["message.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "updated" {
+				t.Fatalf("o.Obj[%d] status must be updated", j)
+			}
+		}
+	}
+
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 2 {
+			t.Fatal("there must be two messages")
+		}
+
+		for _, x := range o.Obj {
+			if x.Metadata["message.venturemark.co/id"] == me1 && x.Property.Text != "foo" {
+				t.Fatal("text must be foo")
+			}
+			if x.Metadata["message.venturemark.co/id"] == me2 && x.Property.Text != "baz" {
+				t.Fatal("text must be baz")
+			}
+		}
+	}
+}
+
+// Test_Batch_005 ensures that venture delete batches spanning a venture the
+// caller does not own are rejected as a whole.
+func Test_Batch_005(t *testing.T) {
+	var err error
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ve1 string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ve1 = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	var ve2 string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "Apple",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ve2 = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve2,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Venture().Delete(context.Background(), i)
+		if err == nil {
+			t.Fatal("error must not be empty")
+		}
+	}
+
+	owners := []struct {
+		cli *client.Client
+		vei string
+	}{
+		{cli: cl1, vei: ve1},
+		{cli: cl2, vei: ve2},
+	}
+
+	for _, x := range owners {
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": x.vei,
+					},
+				},
+			},
+		}
+
+		o, err := x.cli.Venture().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one venture")
+		}
+	}
+}
@@ -0,0 +1,551 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"testing"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+
+	"github.com/venturemark/cfm/pkg/cascade"
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// Test_Cascade_001 ensures that deleting a venture deletes its timelines,
+// updates, messages, invites and roles, while leaving a sibling venture
+// untouched.
+func Test_Cascade_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cas *cascade.Cascade
+	{
+		c := cascade.Config{
+			Client: cli,
+		}
+
+		cas, err = cascade.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ve1 string
+	var ve2 string
+	{
+		ve1 = cascadeVenture(t, cli, "IBM")
+		ve2 = cascadeVenture(t, cli, "Apple")
+	}
+
+	var ids []string
+	{
+		ids = append(ids, cascadeGraph(t, cli, ve1, "Marketing Campaign")...)
+		cascadeGraph(t, cli, ve2, "Marketing Campaign")
+	}
+
+	var sna *cascade.Snapshot
+	{
+		del := cascade.Resource{
+			Kind: cascade.KindVenture,
+			Metadata: map[string]string{
+				"venture.venturemark.co/id": ve1,
+			},
+		}
+
+		sib := cascade.Resource{
+			Kind: cascade.KindVenture,
+			Metadata: map[string]string{
+				"venture.venturemark.co/id": ve2,
+			},
+		}
+
+		sna, err = cas.Snapshot(del, sib)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cascadeContains(t, sna.Deleted, ids)
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+			},
+		}
+
+		_, err := cli.Venture().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = cas.Verify(sna)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test_Cascade_002 ensures that deleting a timeline deletes its updates,
+// messages and roles, while leaving a sibling timeline of the same venture
+// untouched.
+func Test_Cascade_002(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cas *cascade.Cascade
+	{
+		c := cascade.Config{
+			Client: cli,
+		}
+
+		cas, err = cascade.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		vei = cascadeVenture(t, cli, "IBM")
+	}
+
+	var ti1 string
+	var ti2 string
+	var ids []string
+	{
+		ids = cascadeGraph(t, cli, vei, "Marketing Campaign")
+		ti1 = ids[0]
+		ti2 = cascadeGraph(t, cli, vei, "Internal Project")[0]
+	}
+
+	var sna *cascade.Snapshot
+	{
+		del := cascade.Resource{
+			Kind: cascade.KindTimeline,
+			Metadata: map[string]string{
+				"timeline.venturemark.co/id": ti1,
+				"venture.venturemark.co/id":  vei,
+			},
+		}
+
+		sib := cascade.Resource{
+			Kind: cascade.KindTimeline,
+			Metadata: map[string]string{
+				"timeline.venturemark.co/id": ti2,
+				"venture.venturemark.co/id":  vei,
+			},
+		}
+
+		sna, err = cas.Snapshot(del, sib)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The graph created for the venture contains an invite, which is not a
+		// descendant of the timeline.
+		cascadeContains(t, sna.Deleted, ids[:len(ids)-1])
+	}
+
+	{
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{
+							Ope: "replace",
+							Pat: "/obj/property/stat",
+							Val: to.StringP("archived"),
+						},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Update(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &timeline.DeleteI{
+			Obj: []*timeline.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = cas.Verify(sna)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test_Cascade_003 ensures that deleting an update deletes its messages, while
+// leaving a sibling update of the same timeline untouched.
+func Test_Cascade_003(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cas *cascade.Cascade
+	{
+		c := cascade.Config{
+			Client: cli,
+		}
+
+		cas, err = cascade.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		vei = cascadeVenture(t, cli, "IBM")
+	}
+
+	// The graph is created as timeline, role, update, message, update, message
+	// and invite.
+	var tii string
+	var up1 string
+	var up2 string
+	var me1 string
+	{
+		ids := cascadeGraph(t, cli, vei, "Marketing Campaign")
+		tii = ids[0]
+		up1 = ids[2]
+		me1 = ids[3]
+		up2 = ids[4]
+	}
+
+	var sna *cascade.Snapshot
+	{
+		del := cascade.Resource{
+			Kind: cascade.KindUpdate,
+			Metadata: map[string]string{
+				"timeline.venturemark.co/id": tii,
+				"update.venturemark.co/id":   up1,
+				"venture.venturemark.co/id":  vei,
+			},
+		}
+
+		sib := cascade.Resource{
+			Kind: cascade.KindUpdate,
+			Metadata: map[string]string{
+				"timeline.venturemark.co/id": tii,
+				"update.venturemark.co/id":   up2,
+				"venture.venturemark.co/id":  vei,
+			},
+		}
+
+		sna, err = cas.Snapshot(del, sib)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cascadeContains(t, sna.Deleted, []string{up1, me1})
+	}
+
+	{
+		i := &texupd.DeleteI{
+			Obj: []*texupd.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   up1,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		_, err := cli.TexUpd().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = cas.Verify(sna)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// cascadeContains fails the test if any of the given IDs is missing in lis.
+func cascadeContains(t *testing.T, lis []cascade.Resource, ids []string) {
+	for _, s := range ids {
+		var f bool
+		for _, r := range lis {
+			if r.ID() == s {
+				f = true
+			}
+		}
+
+		if !f {
+			t.Fatalf("resource %s must be part of the graph", s)
+		}
+	}
+}
+
+// cascadeGraph creates a timeline with the given name within the given venture, a timeline role,
+// two updates with one message each and an invite. The IDs of the created
+// resources are returned in creation order.
+func cascadeGraph(t *testing.T, cli *client.Client, vei string, nam string) []string {
+	var ids []string
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: nam,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tii = o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		ids = append(ids, tii)
+	}
+
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "timeline",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    "2",
+						"timeline.venturemark.co/id":   tii,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, o.Obj[0].Metadata["role.venturemark.co/id"])
+	}
+
+	for j := 0; j < 2; j++ {
+		var upi string
+		{
+			i := &texupd.CreateI{
+				Obj: []*texupd.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &texupd.CreateI_Obj_Property{
+							Text: "Lorem ipsum",
+						},
+					},
+				},
+			}
+
+			o, err := cli.TexUpd().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			upi = o.Obj[0].Metadata["update.venturemark.co/id"]
+			ids = append(ids, upi)
+		}
+
+		{
+			i := &message.CreateI{
+				Obj: []*message.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   upi,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &message.CreateI_Obj_Property{
+							Text: "Lorem ipsum",
+						},
+					},
+				},
+			}
+
+			o, err := cli.Message().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids = append(ids, o.Obj[0].Metadata["message.venturemark.co/id"])
+		}
+	}
+
+	{
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: "user2@site.net",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Invite().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, o.Obj[0].Metadata["invite.venturemark.co/id"])
+	}
+
+	return ids
+}
+
+func cascadeVenture(t *testing.T, cli *client.Client, nam string) string {
+	i := &venture.CreateI{
+		Obj: []*venture.CreateI_Obj{
+			{
+				Property: &venture.CreateI_Obj_Property{
+					Name: nam,
+				},
+			},
+		},
+	}
+
+	o, err := cli.Venture().Create(context.Background(), i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return o.Obj[0].Metadata["venture.venturemark.co/id"]
+}
@@ -0,0 +1,545 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/venturemark/cfm/pkg/client"
+)
+
+// Test_Client_001 ensures that RPCs are tracked while in flight, that closing
+// a client reports RPCs still in flight and that closing a client is
+// idempotent.
+func Test_Client_001(t *testing.T) {
+	var err error
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, nil)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing listens on the address of cl2, which is why calls waiting for
+	// the connection to become ready stay in flight until they get cancelled.
+	var cl2 *client.Client
+	{
+		c := client.Config{
+			Address:        "127.0.0.1:1",
+			Correlation:    t.Name(),
+			Logger:         t,
+			Near:           near,
+			Nondestructive: *ndst,
+			Span:           span(t),
+		}
+
+		cl2, err = client.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cleanup(t, cl2)
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(cl1.InFlight()) != 0 {
+			t.Fatal("there must be zero calls in flight")
+		}
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		_, err := cl1.User().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = cl1.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cl1.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	don := make(chan error, 1)
+	{
+		go func() {
+			i := &venture.SearchI{}
+
+			_, err := cl2.Venture().Search(context.Background(), i, grpc.WaitForReady(true))
+			don <- err
+		}()
+
+		for j := 0; len(cl2.InFlight()) == 0; j++ {
+			if j == 100 {
+				t.Fatal("there must be one call in flight")
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		if cl2.InFlight()[0].Method != "/venture.API/Search" {
+			t.Fatal("method must match")
+		}
+	}
+
+	{
+		e1 := cl2.Close()
+		if !client.IsClose(e1) {
+			t.Fatalf("expected %#v got %#v", "closeError", e1)
+		}
+
+		if <-don == nil {
+			t.Fatal("error must not be empty")
+		}
+
+		e2 := cl2.Close()
+		if e2 != e1 {
+			t.Fatal("error must match across closes")
+		}
+
+		if len(cl2.InFlight()) != 0 {
+			t.Fatal("there must be zero calls in flight")
+		}
+	}
+}
+
+// Test_Client_002 ensures that RPCs get the default deadline or the deadline
+// of their service unless their context has a deadline already, and that RPCs
+// exceeding their deadline are reported as near their deadline.
+func Test_Client_002(t *testing.T) {
+	var err error
+
+	// Nothing listens on the address of cli, which is why calls waiting for the
+	// connection to become ready run until their deadline exceeds. These calls
+	// are not reported once all tests completed, since they exceed their
+	// deadline on purpose.
+	var cli *client.Client
+	{
+		c := client.Config{
+			Address:     "127.0.0.1:1",
+			Correlation: t.Name(),
+			Logger:      t,
+			Span:        span(t),
+			Timeout:     200 * time.Millisecond,
+			Timeouts: map[string]time.Duration{
+				"venture": 100 * time.Millisecond,
+			},
+		}
+
+		cli, err = client.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cleanup(t, cli)
+	}
+
+	testCases := []struct {
+		ctx func() (context.Context, context.CancelFunc)
+		cal func(ctx context.Context) error
+		dea time.Duration
+	}{
+		// Case 0 ensures that the deadline of the service applies.
+		{
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			cal: func(ctx context.Context) error {
+				_, err := cli.Venture().Search(ctx, &venture.SearchI{}, grpc.WaitForReady(true))
+				return err
+			},
+			dea: 100 * time.Millisecond,
+		},
+		// Case 1 ensures that the default deadline applies.
+		{
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			cal: func(ctx context.Context) error {
+				_, err := cli.User().Search(ctx, &user.SearchI{}, grpc.WaitForReady(true))
+				return err
+			},
+			dea: 200 * time.Millisecond,
+		},
+		// Case 2 ensures that the deadline of the context takes precedence.
+		{
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 400*time.Millisecond)
+			},
+			cal: func(ctx context.Context) error {
+				_, err := cli.Venture().Search(ctx, &venture.SearchI{}, grpc.WaitForReady(true))
+				return err
+			},
+			dea: 400 * time.Millisecond,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			ctx, can := tc.ctx()
+			defer can()
+
+			err := tc.cal(ctx)
+			if status.Code(err) != codes.DeadlineExceeded {
+				t.Fatalf("expected %s got %s", codes.DeadlineExceeded, status.Code(err))
+			}
+
+			nea := cli.Near()
+			if len(nea) != i+1 {
+				t.Fatalf("there must be %d calls near their deadline", i+1)
+			}
+
+			// The deadline is measured once the call got invoked, which is why it
+			// may fall short of the configured deadline by a few milliseconds.
+			d := nea[i].Deadline
+			if d > tc.dea || d < tc.dea-50*time.Millisecond {
+				t.Fatalf("expected %s got %s", tc.dea, d)
+			}
+		})
+	}
+}
+
+// Test_Client_003 ensures that closing a non destructive client deletes every
+// resource created through the client, so that tests can run against shared
+// environments.
+func Test_Client_003(t *testing.T) {
+	var err error
+
+	var cl1 *client.Client
+	{
+		c := client.Config{
+			Correlation:    t.Name(),
+			Logger:         t,
+			Near:           near,
+			Nondestructive: true,
+			Span:           span(t),
+		}
+
+		cl1, err = client.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cleanup(t, cl1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var us1 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		us1 = s
+	}
+
+	var ve1 string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		ve1 = s
+	}
+
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = cl1.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, nil)
+
+	{
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+			},
+		}
+
+		o, err := cl2.Venture().Search(context.Background(), i)
+		if status.Code(err) != codes.NotFound && status.Code(err) != codes.PermissionDenied {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != 0 {
+				t.Fatal("there must be zero ventures")
+			}
+		}
+	}
+
+	{
+		i := &user.SearchI{
+			Obj: []*user.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"subject.venturemark.co/id": us1,
+					},
+				},
+			},
+		}
+
+		o, err := cl2.User().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero users")
+		}
+	}
+}
+
+// Test_Client_004 ensures that closing a non destructive client deletes active
+// timelines created through the client, while the venture they belong to was
+// created by another client and thus is kept.
+func Test_Client_004(t *testing.T) {
+	var err error
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, nil)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cl2 *client.Client
+	{
+		c := client.Config{
+			Correlation:    t.Name(),
+			Logger:         t,
+			Near:           near,
+			Nondestructive: true,
+			Span:           span(t),
+		}
+
+		cl2, err = client.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cleanup(t, cl2)
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ve1 string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		ve1 = s
+	}
+
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		_, err := cl2.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = cl2.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Timeline().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero timelines")
+		}
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": ve1,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Venture().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		_, err := cl1.User().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/compat"
+)
+
+// Test_Compat_001 ensures that the server remains compatible with clients built
+// against older apigengo releases. Requests are built at runtime from the
+// descriptors of each version and sent on the wire. The pinned version is
+// always checked. Further versions are loaded from the directory given by the
+// -compat flag. Every version runs as its own subtest, which makes failing
+// subtests report the client versions that break.
+//
+//	go test ./tst/... -tags conformance -run Test_Compat -args -compat ../compat
+func Test_Compat_001(t *testing.T) {
+	var ver []compat.Version
+	{
+		ver = append(ver, compat.Current())
+
+		if *cmpt != "" {
+			lis, err := compat.Glob(filepath.Join(*cmpt, "*.pb"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ver = append(ver, lis...)
+		}
+	}
+
+	cli := newClient(t, nil)
+
+	var brk []string
+	for _, v := range ver {
+		ok := t.Run(v.Name, func(t *testing.T) {
+			err := purge(cli)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = compatScenario(cli, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		if !ok {
+			brk = append(brk, v.Name)
+		}
+	}
+
+	if len(brk) != 0 {
+		t.Logf("client versions breaking: %v", brk)
+	}
+}
+
+// compatScenario covers the lifecycle of users, ventures, roles, invites,
+// timelines, updates and messages using the definitions of the given version.
+func compatScenario(cli *client.Client, ver compat.Version) error {
+	var err error
+
+	{
+		_, err = compatCall(cli, ver, "user.API/Create", `{"obj":[{"property":{"name":"marcojelli","mail":"m@example.com"}}]}`)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var vei string
+	{
+		met, err := compatCall(cli, ver, "venture.API/Create", `{"obj":[{"property":{"name":"IBM"}}]}`)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		vei = met[0]["venture.venturemark.co/id"]
+		if vei == "" {
+			return tracer.Mask(fmt.Errorf("id must not be empty"))
+		}
+	}
+
+	{
+		met, err := compatCall(cli, ver, "venture.API/Search", fmt.Sprintf(`{"obj":[{"metadata":{"venture.venturemark.co/id":%q}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if len(met) != 1 {
+			return tracer.Mask(fmt.Errorf("there must be one venture"))
+		}
+	}
+
+	var tii string
+	{
+		met, err := compatCall(cli, ver, "timeline.API/Create", fmt.Sprintf(`{"obj":[{"metadata":{"venture.venturemark.co/id":%q},"property":{"name":"Marketing Campaign"}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		tii = met[0]["timeline.venturemark.co/id"]
+		if tii == "" {
+			return tracer.Mask(fmt.Errorf("id must not be empty"))
+		}
+	}
+
+	{
+		met, err := compatCall(cli, ver, "timeline.API/Search", fmt.Sprintf(`{"obj":[{"metadata":{"venture.venturemark.co/id":%q}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if len(met) != 1 {
+			return tracer.Mask(fmt.Errorf("there must be one timeline"))
+		}
+		if met[0]["timeline.venturemark.co/id"] != tii {
+			return tracer.Mask(fmt.Errorf("id must match across actions"))
+		}
+	}
+
+	{
+		_, err = compatCall(cli, ver, "role.API/Create", fmt.Sprintf(`{"obj":[{"metadata":{"resource.venturemark.co/kind":"venture","role.venturemark.co/kind":"member","subject.venturemark.co/id":"1","venture.venturemark.co/id":%q}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		met, err := compatCall(cli, ver, "role.API/Search", fmt.Sprintf(`{"obj":[{"metadata":{"resource.venturemark.co/kind":"venture","venture.venturemark.co/id":%q}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if len(met) != 2 {
+			return tracer.Mask(fmt.Errorf("there must be two roles"))
+		}
+	}
+
+	var ini string
+	{
+		met, err := compatCall(cli, ver, "invite.API/Create", fmt.Sprintf(`{"obj":[{"metadata":{"venture.venturemark.co/id":%q},"property":{"mail":"user1@site.net"}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		ini = met[0]["invite.venturemark.co/id"]
+		if ini == "" {
+			return tracer.Mask(fmt.Errorf("id must not be empty"))
+		}
+	}
+
+	{
+		met, err := compatCall(cli, ver, "invite.API/Search", fmt.Sprintf(`{"obj":[{"metadata":{"venture.venturemark.co/id":%q}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if len(met) != 1 {
+			return tracer.Mask(fmt.Errorf("there must be one invite"))
+		}
+		if met[0]["invite.venturemark.co/id"] != ini {
+			return tracer.Mask(fmt.Errorf("id must match across actions"))
+		}
+	}
+
+	var upi string
+	{
+		met, err := compatCall(cli, ver, "texupd.API/Create", fmt.Sprintf(`{"obj":[{"metadata":{"timeline.venturemark.co/id":%q,"venture.venturemark.co/id":%q},"property":{"text":"Lorem ipsum 1"}}]}`, tii, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		upi = met[0]["update.venturemark.co/id"]
+		if upi == "" {
+			return tracer.Mask(fmt.Errorf("id must not be empty"))
+		}
+	}
+
+	{
+		met, err := compatCall(cli, ver, "update.API/Search", fmt.Sprintf(`{"obj":[{"metadata":{"timeline.venturemark.co/id":%q,"venture.venturemark.co/id":%q}}]}`, tii, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if len(met) != 1 {
+			return tracer.Mask(fmt.Errorf("there must be one update"))
+		}
+		if met[0]["update.venturemark.co/id"] != upi {
+			return tracer.Mask(fmt.Errorf("id must match across actions"))
+		}
+	}
+
+	var mei string
+	{
+		met, err := compatCall(cli, ver, "message.API/Create", fmt.Sprintf(`{"obj":[{"metadata":{"timeline.venturemark.co/id":%q,"update.venturemark.co/id":%q,"venture.venturemark.co/id":%q},"property":{"text":"Lorem ipsum 1"}}]}`, tii, upi, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		mei = met[0]["message.venturemark.co/id"]
+		if mei == "" {
+			return tracer.Mask(fmt.Errorf("id must not be empty"))
+		}
+	}
+
+	{
+		met, err := compatCall(cli, ver, "message.API/Search", fmt.Sprintf(`{"obj":[{"metadata":{"timeline.venturemark.co/id":%q,"update.venturemark.co/id":%q,"venture.venturemark.co/id":%q}}]}`, tii, upi, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if len(met) != 1 {
+			return tracer.Mask(fmt.Errorf("there must be one message"))
+		}
+		if met[0]["message.venturemark.co/id"] != mei {
+			return tracer.Mask(fmt.Errorf("id must match across actions"))
+		}
+	}
+
+	{
+		_, err = compatCall(cli, ver, "venture.API/Delete", fmt.Sprintf(`{"obj":[{"metadata":{"venture.venturemark.co/id":%q}}]}`, vei))
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	{
+		_, err = compatCall(cli, ver, "user.API/Delete", `{}`)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+// compatCall invokes met using the definitions of ver and returns the metadata
+// of all objects of the response.
+func compatCall(cli *client.Client, ver compat.Version, met string, req string) ([]map[string]string, error) {
+	res, err := ver.Call(context.Background(), cli.Grpc(), met, req)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	var out struct {
+		Obj []struct {
+			Metadata map[string]string `json:"metadata"`
+		} `json:"obj"`
+	}
+
+	err = json.Unmarshal([]byte(res), &out)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	var lis []map[string]string
+	for _, o := range out.Obj {
+		lis = append(lis, o.Metadata)
+	}
+
+	return lis, nil
+}
@@ -0,0 +1,1083 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/budget"
+	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/keyspace"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// The fuzz targets in this file generate random CreateI and UpdateI payloads
+// for every service. For every generated payload the following must hold.
+//
+//     * The server never responds with codes.Internal or codes.Unknown.
+//     * Rejected requests do not change the Redis keyspace.
+//     * Accepted creates leave no keys behind once the created resources are
+//       deleted again.
+//
+// Without the -fuzz flag only the seed corpus below is executed, which is what
+// happens in CI. All fuzz targets share the same Redis instance and must
+// therefore never run concurrently, e.g.
+//
+//     go test ./tst -tags conformance -run '^$' -fuzz '^Fuzz_Venture_Create$' -parallel 1
+//
+
+const (
+	// fuzzObj is the maximum number of objects sent within a single request.
+	fuzzObj = 16
+)
+
+func Fuzz_User_Create(f *testing.F) {
+	fuzzCreateSeed(f)
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+
+	f.Fuzz(func(t *testing.T, key string, val string, txt string, num uint8) {
+		fuzzValid(t, key, val, txt)
+
+		bef := fuzzKeys(t, cli)
+
+		i := &user.CreateI{}
+		for j := 0; j < int(num%fuzzObj); j++ {
+			o := &user.CreateI_Obj{
+				Metadata: fuzzMetadata(nil, key, val),
+			}
+
+			if txt != "" {
+				o.Property = &user.CreateI_Obj_Property{
+					Desc: txt,
+					Mail: txt,
+					Name: txt,
+					Prof: []*user.CreateI_Obj_Property_Prof{
+						{
+							Desc: txt,
+							Vent: txt,
+						},
+					},
+				}
+			}
+
+			i.Obj = append(i.Obj, o)
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err == nil {
+			for _, x := range o.Obj {
+				i := &user.DeleteI{
+					Obj: []*user.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"user.venturemark.co/id": x.Metadata["user.venturemark.co/id"],
+							},
+						},
+					},
+				}
+
+				_, err := cli.User().Delete(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		fuzzOrphans(t, cli, bef)
+	})
+}
+
+func Fuzz_User_Update(f *testing.F) {
+	fuzzUpdateSeed(f, "/obj/property/name")
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	usi := fuzzUser(f, cli, "marcojelli")
+
+	f.Fuzz(func(t *testing.T, key string, ope string, pat string, val string) {
+		fuzzValid(t, key, ope, pat, val)
+
+		met := map[string]string{
+			"user.venturemark.co/id": usi,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &user.UpdateI{
+			Obj: []*user.UpdateI_Obj{
+				{
+					Metadata: fuzzMetadata(met, key, val),
+					Jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+						{Ope: ope, Pat: pat, Val: fuzzVal(val)},
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Update(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err != nil {
+			fuzzOrphans(t, cli, bef)
+		}
+	})
+}
+
+func Fuzz_Venture_Create(f *testing.F) {
+	fuzzCreateSeed(f)
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+
+	f.Fuzz(func(t *testing.T, key string, val string, txt string, num uint8) {
+		fuzzValid(t, key, val, txt)
+
+		bef := fuzzKeys(t, cli)
+
+		i := &venture.CreateI{}
+		for j := 0; j < int(num%fuzzObj); j++ {
+			o := &venture.CreateI_Obj{
+				Metadata: fuzzMetadata(nil, key, val),
+			}
+
+			if txt != "" {
+				o.Property = &venture.CreateI_Obj_Property{
+					Desc: txt,
+					Link: []*venture.CreateI_Obj_Property_Link{
+						{
+							Addr: txt,
+							Text: txt,
+						},
+					},
+					Name: txt,
+				}
+			}
+
+			i.Obj = append(i.Obj, o)
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err == nil {
+			for _, x := range o.Obj {
+				i := &venture.DeleteI{
+					Obj: []*venture.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"venture.venturemark.co/id": x.Metadata["venture.venturemark.co/id"],
+							},
+						},
+					},
+				}
+
+				_, err := cli.Venture().Delete(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		fuzzOrphans(t, cli, bef)
+	})
+}
+
+func Fuzz_Venture_Update(f *testing.F) {
+	fuzzUpdateSeed(f, "/obj/property/name")
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+
+	f.Fuzz(func(t *testing.T, key string, ope string, pat string, val string) {
+		fuzzValid(t, key, ope, pat, val)
+
+		met := map[string]string{
+			"venture.venturemark.co/id": vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &venture.UpdateI{
+			Obj: []*venture.UpdateI_Obj{
+				{
+					Metadata: fuzzMetadata(met, key, val),
+					Jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+						{Ope: ope, Pat: pat, Val: fuzzVal(val)},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Venture().Update(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err != nil {
+			fuzzOrphans(t, cli, bef)
+		}
+	})
+}
+
+func Fuzz_Timeline_Create(f *testing.F) {
+	fuzzCreateSeed(f)
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+
+	f.Fuzz(func(t *testing.T, key string, val string, txt string, num uint8) {
+		fuzzValid(t, key, val, txt)
+
+		met := map[string]string{
+			"venture.venturemark.co/id": vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &timeline.CreateI{}
+		for j := 0; j < int(num%fuzzObj); j++ {
+			o := &timeline.CreateI_Obj{
+				Metadata: fuzzMetadata(met, key, val),
+			}
+
+			if txt != "" {
+				o.Property = &timeline.CreateI_Obj_Property{
+					Desc: txt,
+					Name: txt,
+				}
+			}
+
+			i.Obj = append(i.Obj, o)
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err == nil {
+			for _, x := range o.Obj {
+				met := map[string]string{
+					"timeline.venturemark.co/id": x.Metadata["timeline.venturemark.co/id"],
+					"venture.venturemark.co/id":  vei,
+				}
+
+				// Timelines can only be deleted once they got archived.
+				{
+					i := &timeline.UpdateI{
+						Obj: []*timeline.UpdateI_Obj{
+							{
+								Metadata: met,
+								Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+									{Ope: "replace", Pat: "/obj/property/stat", Val: to.StringP("archived")},
+								},
+							},
+						},
+					}
+
+					_, err := cli.Timeline().Update(context.Background(), i)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				{
+					i := &timeline.DeleteI{
+						Obj: []*timeline.DeleteI_Obj{
+							{
+								Metadata: met,
+							},
+						},
+					}
+
+					_, err := cli.Timeline().Delete(context.Background(), i)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+
+		fuzzOrphans(t, cli, bef)
+	})
+}
+
+func Fuzz_Timeline_Update(f *testing.F) {
+	fuzzUpdateSeed(f, "/obj/property/desc")
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+	tii := fuzzTimeline(f, cli, vei)
+
+	f.Fuzz(func(t *testing.T, key string, ope string, pat string, val string) {
+		fuzzValid(t, key, ope, pat, val)
+
+		met := map[string]string{
+			"timeline.venturemark.co/id": tii,
+			"venture.venturemark.co/id":  vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: fuzzMetadata(met, key, val),
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{Ope: ope, Pat: pat, Val: fuzzVal(val)},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Update(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err != nil {
+			fuzzOrphans(t, cli, bef)
+		}
+	})
+}
+
+func Fuzz_TexUpd_Create(f *testing.F) {
+	fuzzCreateSeed(f)
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+	tii := fuzzTimeline(f, cli, vei)
+
+	f.Fuzz(func(t *testing.T, key string, val string, txt string, num uint8) {
+		fuzzValid(t, key, val, txt)
+
+		met := map[string]string{
+			"timeline.venturemark.co/id": tii,
+			"venture.venturemark.co/id":  vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &texupd.CreateI{}
+		for j := 0; j < int(num%fuzzObj); j++ {
+			o := &texupd.CreateI_Obj{
+				Metadata: fuzzMetadata(met, key, val),
+			}
+
+			if txt != "" {
+				o.Property = &texupd.CreateI_Obj_Property{
+					Head: txt,
+					Text: txt,
+				}
+			}
+
+			i.Obj = append(i.Obj, o)
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err == nil {
+			for _, x := range o.Obj {
+				i := &texupd.DeleteI{
+					Obj: []*texupd.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": tii,
+								"update.venturemark.co/id":   x.Metadata["update.venturemark.co/id"],
+								"venture.venturemark.co/id":  vei,
+							},
+						},
+					},
+				}
+
+				_, err := cli.TexUpd().Delete(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		fuzzOrphans(t, cli, bef)
+	})
+}
+
+func Fuzz_TexUpd_Update(f *testing.F) {
+	fuzzUpdateSeed(f, "/obj/property/text")
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+	tii := fuzzTimeline(f, cli, vei)
+	upi := fuzzTexUpd(f, cli, vei, tii)
+
+	f.Fuzz(func(t *testing.T, key string, ope string, pat string, val string) {
+		fuzzValid(t, key, ope, pat, val)
+
+		met := map[string]string{
+			"timeline.venturemark.co/id": tii,
+			"update.venturemark.co/id":   upi,
+			"venture.venturemark.co/id":  vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &texupd.UpdateI{
+			Obj: []*texupd.UpdateI_Obj{
+				{
+					Metadata: fuzzMetadata(met, key, val),
+					Jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+						{Ope: ope, Pat: pat, Val: fuzzVal(val)},
+					},
+				},
+			},
+		}
+
+		_, err := cli.TexUpd().Update(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err != nil {
+			fuzzOrphans(t, cli, bef)
+		}
+	})
+}
+
+func Fuzz_Message_Create(f *testing.F) {
+	fuzzCreateSeed(f)
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+	tii := fuzzTimeline(f, cli, vei)
+	upi := fuzzTexUpd(f, cli, vei, tii)
+
+	f.Fuzz(func(t *testing.T, key string, val string, txt string, num uint8) {
+		fuzzValid(t, key, val, txt)
+
+		met := map[string]string{
+			"timeline.venturemark.co/id": tii,
+			"update.venturemark.co/id":   upi,
+			"venture.venturemark.co/id":  vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &message.CreateI{}
+		for j := 0; j < int(num%fuzzObj); j++ {
+			o := &message.CreateI_Obj{
+				Metadata: fuzzMetadata(met, key, val),
+			}
+
+			if txt != "" {
+				o.Property = &message.CreateI_Obj_Property{
+					Reid: txt,
+					Text: txt,
+				}
+			}
+
+			i.Obj = append(i.Obj, o)
+		}
+
+		o, err := cli.Message().Create(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err == nil {
+			for _, x := range o.Obj {
+				i := &message.DeleteI{
+					Obj: []*message.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"message.venturemark.co/id":  x.Metadata["message.venturemark.co/id"],
+								"timeline.venturemark.co/id": tii,
+								"update.venturemark.co/id":   upi,
+								"venture.venturemark.co/id":  vei,
+							},
+						},
+					},
+				}
+
+				_, err := cli.Message().Delete(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		fuzzOrphans(t, cli, bef)
+	})
+}
+
+func Fuzz_Message_Update(f *testing.F) {
+	fuzzUpdateSeed(f, "/obj/property/text")
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+	tii := fuzzTimeline(f, cli, vei)
+	upi := fuzzTexUpd(f, cli, vei, tii)
+	mei := fuzzMessage(f, cli, vei, tii, upi)
+
+	f.Fuzz(func(t *testing.T, key string, ope string, pat string, val string) {
+		fuzzValid(t, key, ope, pat, val)
+
+		met := map[string]string{
+			"message.venturemark.co/id":  mei,
+			"timeline.venturemark.co/id": tii,
+			"update.venturemark.co/id":   upi,
+			"venture.venturemark.co/id":  vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &message.UpdateI{
+			Obj: []*message.UpdateI_Obj{
+				{
+					Metadata: fuzzMetadata(met, key, val),
+					Jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+						{Ope: ope, Pat: pat, Val: fuzzVal(val)},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Message().Update(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err != nil {
+			fuzzOrphans(t, cli, bef)
+		}
+	})
+}
+
+func Fuzz_Invite_Create(f *testing.F) {
+	fuzzCreateSeed(f)
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+
+	f.Fuzz(func(t *testing.T, key string, val string, txt string, num uint8) {
+		fuzzValid(t, key, val, txt)
+
+		met := map[string]string{
+			"venture.venturemark.co/id": vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &invite.CreateI{}
+		for j := 0; j < int(num%fuzzObj); j++ {
+			o := &invite.CreateI_Obj{
+				Metadata: fuzzMetadata(met, key, val),
+			}
+
+			if txt != "" {
+				o.Property = &invite.CreateI_Obj_Property{
+					Mail: txt,
+				}
+			}
+
+			i.Obj = append(i.Obj, o)
+		}
+
+		o, err := cli.Invite().Create(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err == nil {
+			for _, x := range o.Obj {
+				i := &invite.DeleteI{
+					Obj: []*invite.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"invite.venturemark.co/id":  x.Metadata["invite.venturemark.co/id"],
+								"venture.venturemark.co/id": vei,
+							},
+						},
+					},
+				}
+
+				_, err := cli.Invite().Delete(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		fuzzOrphans(t, cli, bef)
+	})
+}
+
+func Fuzz_Invite_Update(f *testing.F) {
+	fuzzUpdateSeed(f, "/obj/property/stat")
+
+	cl1 := fuzzClient(f, oauth.NewInsecureOne())
+	cl2 := fuzzClient(f, oauth.NewInsecureTwo())
+	fuzzUser(f, cl1, "marcojelli")
+	fuzzUser(f, cl2, "disreszi")
+	vei := fuzzVenture(f, cl1)
+
+	var cod string
+	var ini string
+	{
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: "user2@site.net",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Invite().Create(context.Background(), i)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		cod = o.Obj[0].Metadata["invite.venturemark.co/code"]
+		ini = o.Obj[0].Metadata["invite.venturemark.co/id"]
+	}
+
+	f.Fuzz(func(t *testing.T, key string, ope string, pat string, val string) {
+		fuzzValid(t, key, ope, pat, val)
+
+		met := map[string]string{
+			"invite.venturemark.co/code":   cod,
+			"invite.venturemark.co/id":     ini,
+			"resource.venturemark.co/kind": "venture",
+			"role.venturemark.co/kind":     "member",
+			"venture.venturemark.co/id":    vei,
+		}
+
+		bef := fuzzKeys(t, cl2)
+
+		i := &invite.UpdateI{
+			Obj: []*invite.UpdateI_Obj{
+				{
+					Metadata: fuzzMetadata(met, key, val),
+					Jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+						{Ope: ope, Pat: pat, Val: fuzzVal(val)},
+					},
+				},
+			},
+		}
+
+		_, err := cl2.Invite().Update(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err != nil {
+			fuzzOrphans(t, cl2, bef)
+		}
+	})
+}
+
+func Fuzz_Role_Create(f *testing.F) {
+	fuzzCreateSeed(f)
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+
+	f.Fuzz(func(t *testing.T, key string, val string, txt string, num uint8) {
+		fuzzValid(t, key, val, txt)
+
+		met := map[string]string{
+			"resource.venturemark.co/kind": "venture",
+			"role.venturemark.co/kind":     txt,
+			"subject.venturemark.co/id":    "2",
+			"venture.venturemark.co/id":    vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &role.CreateI{}
+		for j := 0; j < int(num%fuzzObj); j++ {
+			o := &role.CreateI_Obj{
+				Metadata: fuzzMetadata(met, key, val),
+			}
+
+			i.Obj = append(i.Obj, o)
+		}
+
+		o, err := cli.Role().Create(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err == nil {
+			for _, x := range o.Obj {
+				i := &role.DeleteI{
+					Obj: []*role.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"resource.venturemark.co/kind": "venture",
+								"role.venturemark.co/id":       x.Metadata["role.venturemark.co/id"],
+								"venture.venturemark.co/id":    vei,
+							},
+						},
+					},
+				}
+
+				_, err := cli.Role().Delete(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		fuzzOrphans(t, cli, bef)
+	})
+}
+
+func Fuzz_Role_Update(f *testing.F) {
+	fuzzUpdateSeed(f, "/obj/metadata/role.venturemark.co~1kind")
+
+	cli := fuzzClient(f, oauth.NewInsecureOne())
+	fuzzUser(f, cli, "marcojelli")
+	vei := fuzzVenture(f, cli)
+
+	var roi string
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    "2",
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Create(context.Background(), i)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		roi = o.Obj[0].Metadata["role.venturemark.co/id"]
+	}
+
+	f.Fuzz(func(t *testing.T, key string, ope string, pat string, val string) {
+		fuzzValid(t, key, ope, pat, val)
+
+		met := map[string]string{
+			"resource.venturemark.co/kind": "venture",
+			"role.venturemark.co/id":       roi,
+			"venture.venturemark.co/id":    vei,
+		}
+
+		bef := fuzzKeys(t, cli)
+
+		i := &role.UpdateI{
+			Obj: []*role.UpdateI_Obj{
+				{
+					Metadata: fuzzMetadata(met, key, val),
+					Jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+						{Ope: ope, Pat: pat, Val: fuzzVal(val)},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Role().Update(context.Background(), i)
+		fuzzCode(t, err)
+
+		if err != nil {
+			fuzzOrphans(t, cli, bef)
+		}
+	})
+}
+
+// fuzzCreateSeed adds the seed corpus shared by all create targets. The
+// arguments are an additional metadata key and value, the text used for every
+// property and the number of objects sent within the request.
+func fuzzCreateSeed(f *testing.F) {
+	f.Add("", "", "", uint8(0))
+	f.Add("", "", "", uint8(1))
+	f.Add("", "", "Lorem ipsum", uint8(1))
+	f.Add("", "", "Lorem ipsum", uint8(fuzzObj-1))
+	f.Add("unknown.venturemark.co/id", "1", "Lorem ipsum", uint8(1))
+	f.Add("venture.venturemark.co/id", "", "Lorem ipsum", uint8(1))
+	f.Add("ключ", "値", "🚀 Grüße aus München 日本語", uint8(2))
+	f.Add("\u0000", "\u200b", "\u202e\u0000\ufeff", uint8(1))
+	f.Add(strings.Repeat("k", 1<<12), strings.Repeat("v", 1<<16), strings.Repeat("Lorem ipsum ", 1<<16), uint8(1))
+}
+
+// fuzzUpdateSeed adds the seed corpus shared by all update targets. The
+// arguments are an additional metadata key, the patch operation, the patch
+// path and a value used for both, the metadata and the patch. The given path
+// is expected to be valid for the resource under test.
+func fuzzUpdateSeed(f *testing.F, pat string) {
+	f.Add("", "replace", pat, "Lorem ipsum")
+	f.Add("", "add", pat, "Lorem ipsum")
+	f.Add("", "remove", pat, "")
+	f.Add("", "test", pat, "wrong")
+	f.Add("", "move", pat, "/obj/property")
+	f.Add("", "copy", pat, "/obj/property")
+	f.Add("", "", "", "")
+	f.Add("", "replace", "", "{}")
+	f.Add("", "replace", "/", "")
+	f.Add("", "replace", "/obj/property/unknown", "Lorem ipsum")
+	f.Add("", "replace", "/obj/property/prof/99999999999999999999/desc", "Lorem ipsum")
+	f.Add("", "replace", "/obj/metadata/~2", "Lorem ipsum")
+	f.Add("unknown.venturemark.co/id", "replace", pat, "1")
+	f.Add("ключ", "replace", pat, "🚀 Grüße aus München 日本語")
+	f.Add("\u0000", "replace", pat, "\u202e\u0000\ufeff")
+	f.Add(strings.Repeat("k", 1<<12), "replace", pat, strings.Repeat("Lorem ipsum ", 1<<16))
+}
+
+// fuzzClient returns a client for the given credentials and purges Redis, so
+// that every fuzz target starts with an empty keyspace. Clients must thus be
+// created before any fixture. Note that the client does not log, since the
+// methods of *testing.F must not be called within fuzz targets.
+func fuzzClient(f *testing.F, cre *oauth.Insecure) *client.Client {
+	c := client.Config{
+		Correlation:    f.Name(),
+		Credentials:    cre,
+		Near:           near,
+		Nondestructive: *ndst,
+		Span:           span(f),
+	}
+
+	cli, err := client.New(c)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	err = purge(cli)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	cleanup(f, cli)
+
+	return cli
+}
+
+// fuzzCode fails the test if err carries a status code the server must never
+// respond with, regardless the request.
+func fuzzCode(t *testing.T, err error) {
+	c := status.Code(err)
+	if c == codes.Internal || c == codes.Unknown {
+		t.Fatalf("code must not be %s: %s", c, err)
+	}
+}
+
+func fuzzKeys(t *testing.T, cli *client.Client) []string {
+	key, err := keyspace.Search(cli.Redigo())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// fuzzMetadata returns a copy of met with the additional key set to val. An
+// empty key leaves the copy untouched. Note that key may overwrite metadata
+// the fixtures of a fuzz target depend on.
+func fuzzMetadata(met map[string]string, key string, val string) map[string]string {
+	cop := map[string]string{}
+	for k, v := range met {
+		cop[k] = v
+	}
+
+	if key != "" {
+		cop[key] = val
+	}
+
+	return cop
+}
+
+// fuzzOrphans fails the test if the Redis keyspace differs from bef. The
+// apiworker cascades deletions asynchronously, which is why the keyspace is
+// compared within a budget.
+func fuzzOrphans(t *testing.T, cli *client.Client, bef []string) {
+	var err error
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	o := func() error {
+		aft, err := keyspace.Search(cli.Redigo())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		add := keyspace.Added(bef, aft)
+		if len(add) != 0 {
+			return tracer.Mask(fmt.Errorf("keys must not be orphaned: %v", add))
+		}
+
+		rem := keyspace.Removed(bef, aft)
+		if len(rem) != 0 {
+			return tracer.Mask(fmt.Errorf("keys must not be removed: %v", rem))
+		}
+
+		return nil
+	}
+
+	err = b.Execute(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fuzzVal returns nil for the empty string so that patches without value are
+// generated as well.
+func fuzzVal(val string) *string {
+	if val == "" {
+		return nil
+	}
+
+	return to.StringP(val)
+}
+
+// fuzzValid skips inputs which are not valid UTF-8, since protobuf strings
+// cannot be marshalled on the client side in that case.
+func fuzzValid(t *testing.T, str ...string) {
+	for _, s := range str {
+		if !utf8.ValidString(s) {
+			t.Skip("input must be valid UTF-8")
+		}
+	}
+}
+
+func fuzzMessage(f *testing.F, cli *client.Client, vei string, tii string, upi string) string {
+	i := &message.CreateI{
+		Obj: []*message.CreateI_Obj{
+			{
+				Metadata: map[string]string{
+					"timeline.venturemark.co/id": tii,
+					"update.venturemark.co/id":   upi,
+					"venture.venturemark.co/id":  vei,
+				},
+				Property: &message.CreateI_Obj_Property{
+					Text: "Lorem ipsum",
+				},
+			},
+		},
+	}
+
+	o, err := cli.Message().Create(context.Background(), i)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	return o.Obj[0].Metadata["message.venturemark.co/id"]
+}
+
+func fuzzTexUpd(f *testing.F, cli *client.Client, vei string, tii string) string {
+	i := &texupd.CreateI{
+		Obj: []*texupd.CreateI_Obj{
+			{
+				Metadata: map[string]string{
+					"timeline.venturemark.co/id": tii,
+					"venture.venturemark.co/id":  vei,
+				},
+				Property: &texupd.CreateI_Obj_Property{
+					Head: "title",
+					Text: "Lorem ipsum",
+				},
+			},
+		},
+	}
+
+	o, err := cli.TexUpd().Create(context.Background(), i)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	return o.Obj[0].Metadata["update.venturemark.co/id"]
+}
+
+func fuzzTimeline(f *testing.F, cli *client.Client, vei string) string {
+	i := &timeline.CreateI{
+		Obj: []*timeline.CreateI_Obj{
+			{
+				Metadata: map[string]string{
+					"venture.venturemark.co/id": vei,
+				},
+				Property: &timeline.CreateI_Obj_Property{
+					Name: "Marketing Campaign",
+				},
+			},
+		},
+	}
+
+	o, err := cli.Timeline().Create(context.Background(), i)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	return o.Obj[0].Metadata["timeline.venturemark.co/id"]
+}
+
+func fuzzUser(f *testing.F, cli *client.Client, nam string) string {
+	i := &user.CreateI{
+		Obj: []*user.CreateI_Obj{
+			{
+				Property: &user.CreateI_Obj_Property{
+					Name: nam,
+					Mail: nam + "@example.com",
+				},
+			},
+		},
+	}
+
+	o, err := cli.User().Create(context.Background(), i)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	return o.Obj[0].Metadata["user.venturemark.co/id"]
+}
+
+func fuzzVenture(f *testing.F, cli *client.Client) string {
+	i := &venture.CreateI{
+		Obj: []*venture.CreateI_Obj{
+			{
+				Property: &venture.CreateI_Obj_Property{
+					Name: "IBM",
+				},
+			},
+		},
+	}
+
+	o, err := cli.Venture().Create(context.Background(), i)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	return o.Obj[0].Metadata["venture.venturemark.co/id"]
+}
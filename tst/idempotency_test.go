@@ -0,0 +1,1849 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/update"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/budget"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// The tests in this file replay the exact same CreateI and DeleteI requests
+// the way a retrying gRPC client would do. A replayed request is considered
+// retry safe if it is either rejected, or if it is a no-op returning the very
+// same resource ID as the original request. Neither apigengo nor the apiserver
+// document the status code of a rejected replay, which is why any error is
+// accepted. In no case must a replay create a second resource or affect any
+// other resource, which is verified after every replay.
+
+// Test_Idempotency_001 ensures that replaying user creation and deletion is
+// retry safe.
+func Test_Idempotency_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var usi string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		usi = s
+
+		o, err = cli.User().Create(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+			if s != usi {
+				t.Fatal("id must match across retries")
+			}
+		}
+	}
+
+	{
+		i := &user.SearchI{
+			Obj: []*user.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"user.venturemark.co/id": usi,
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one user")
+		}
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		o, err := cli.User().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+
+		o, err = cli.User().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["user.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		emp, err := empty(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !emp {
+			t.Fatal("storage must be empty")
+		}
+	}
+}
+
+// Test_Idempotency_002 ensures that replaying venture creation and deletion is
+// retry safe.
+func Test_Idempotency_002(t *testing.T) {
+	var err error
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var usi string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		usi = s
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+
+		o, err = cli.Venture().Create(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+			if s != vei {
+				t.Fatal("id must match across retries")
+			}
+		}
+	}
+
+	{
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"subject.venturemark.co/id": usi,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one venture")
+		}
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one role")
+		}
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+
+		o, err = cli.Venture().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["venture.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		o := func() error {
+			i := &role.SearchI{
+				Obj: []*role.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"resource.venturemark.co/kind": "venture",
+							"venture.venturemark.co/id":    vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Role().Search(context.Background(), i)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if len(o.Obj) != 0 {
+				return tracer.Mask(fmt.Errorf("there must be zero roles"))
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		_, err := cli.User().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		o := func() error {
+			emp, err := empty(cli)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !emp {
+				return tracer.Mask(fmt.Errorf("storage must be empty"))
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test_Idempotency_003 ensures that replaying timeline creation and deletion is
+// retry safe.
+func Test_Idempotency_003(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+
+		o, err = cli.Timeline().Create(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+			if s != tii {
+				t.Fatal("id must match across retries")
+			}
+		}
+	}
+
+	{
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one timeline")
+		}
+	}
+
+	{
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{
+							Ope: "replace",
+							Pat: "/obj/property/stat",
+							Val: to.StringP("archived"),
+						},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Update(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &timeline.DeleteI{
+			Obj: []*timeline.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+
+		o, err = cli.Timeline().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["timeline.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero timelines")
+		}
+	}
+}
+
+// Test_Idempotency_004 ensures that replaying text update creation and
+// deletion is retry safe.
+func Test_Idempotency_004(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Text: "Lorem ipsum 1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+
+		o, err = cli.TexUpd().Create(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+			if s != upi {
+				t.Fatal("id must match across retries")
+			}
+		}
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one update")
+		}
+	}
+
+	{
+		i := &texupd.DeleteI{
+			Obj: []*texupd.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+
+		o, err = cli.TexUpd().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["update.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero updates")
+		}
+	}
+}
+
+// Test_Idempotency_005 ensures that replaying message creation and deletion is
+// retry safe.
+func Test_Idempotency_005(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Text: "Lorem ipsum 1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
+	var mei string
+	{
+		i := &message.CreateI{
+			Obj: []*message.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: "Lorem ipsum 1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		mei = s
+
+		o, err = cli.Message().Create(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+			if s != mei {
+				t.Fatal("id must match across retries")
+			}
+		}
+	}
+
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one message")
+		}
+	}
+
+	{
+		i := &message.DeleteI{
+			Obj: []*message.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"message.venturemark.co/id":  mei,
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["message.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+
+		o, err = cli.Message().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["message.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero messages")
+		}
+	}
+}
+
+// Test_Idempotency_006 ensures that replaying invite creation and deletion is
+// retry safe.
+func Test_Idempotency_006(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var ini string
+	{
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: "user1@site.net",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Invite().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["invite.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		ini = s
+
+		o, err = cli.Invite().Create(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["invite.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+			if s != ini {
+				t.Fatal("id must match across retries")
+			}
+		}
+	}
+
+	{
+		i := &invite.SearchI{
+			Obj: []*invite.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Invite().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one invite")
+		}
+	}
+
+	{
+		i := &invite.DeleteI{
+			Obj: []*invite.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"invite.venturemark.co/id":  ini,
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Invite().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["invite.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+
+		o, err = cli.Invite().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["invite.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &invite.SearchI{
+			Obj: []*invite.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Invite().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero invites")
+		}
+	}
+}
+
+// Test_Idempotency_007 ensures that replaying role creation and deletion is
+// retry safe.
+func Test_Idempotency_007(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var roi string
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    "1",
+						"venture.venturemark.co/id":    "1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["role.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		roi = s
+
+		o, err = cli.Role().Create(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["role.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+			if s != roi {
+				t.Fatal("id must match across retries")
+			}
+		}
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    "1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one role")
+		}
+	}
+
+	{
+		i := &role.DeleteI{
+			Obj: []*role.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/id":       roi,
+						"venture.venturemark.co/id":    "1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["role.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+
+		o, err = cli.Role().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["role.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    "1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero roles")
+		}
+	}
+}
+
+// Test_Idempotency_008 ensures that replaying deletion requests after the
+// apiworker cascaded the deletion of a timeline is retry safe. Neither the
+// timeline nor any of its updates and messages must be resurrected by the
+// replayed requests, while sibling resources stay untouched.
+func Test_Idempotency_008(t *testing.T) {
+	var err error
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var ti1 string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		ti1 = s
+	}
+
+	var ti2 string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Internal Project",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		ti2 = s
+	}
+
+	var up1 string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Text: "Lorem ipsum 1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		up1 = s
+	}
+
+	var up2 string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti2,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Text: "Lorem ipsum 2",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		up2 = s
+	}
+
+	var me1 string
+	{
+		i := &message.CreateI{
+			Obj: []*message.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"update.venturemark.co/id":   up1,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: "Lorem ipsum 1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		me1 = s
+	}
+
+	{
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{
+							Ope: "replace",
+							Pat: "/obj/property/stat",
+							Val: to.StringP("archived"),
+						},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Update(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tdi *timeline.DeleteI
+	{
+		tdi = &timeline.DeleteI{
+			Obj: []*timeline.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Delete(context.Background(), tdi)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+	}
+
+	// Here we wait for the apiworker to cascade the timeline deletion before
+	// replaying any request. Only then we can be sure that the replays hit a
+	// system in which the affected resources are gone for good.
+
+	{
+		o := func() error {
+			i := &update.SearchI{
+				Obj: []*update.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": ti1,
+							"venture.venturemark.co/id":  vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Update().Search(context.Background(), i)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if len(o.Obj) != 0 {
+				return tracer.Mask(fmt.Errorf("there must be zero updates"))
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		o, err := cli.Timeline().Delete(context.Background(), tdi)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["timeline.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &texupd.DeleteI{
+			Obj: []*texupd.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"update.venturemark.co/id":   up1,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["update.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &message.DeleteI{
+			Obj: []*message.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"message.venturemark.co/id":  me1,
+						"timeline.venturemark.co/id": ti1,
+						"update.venturemark.co/id":   up1,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Delete(context.Background(), i)
+		if err == nil {
+			s, ok := o.Obj[0].Metadata["message.venturemark.co/status"]
+			if !ok {
+				t.Fatal("status must not be empty")
+			}
+			if s != "deleted" {
+				t.Fatal("status must be deleted")
+			}
+		}
+	}
+
+	{
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one timeline")
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+		if s != ti2 {
+			t.Fatal("id must match across actions")
+		}
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero updates")
+		}
+	}
+
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"update.venturemark.co/id":   up1,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero messages")
+		}
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti2,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one update")
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+		if s != up2 {
+			t.Fatal("id must match across actions")
+		}
+	}
+}
@@ -0,0 +1,985 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/update"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/budget"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/keyspace"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// Test_Isolation_001 audits the data isolation between ventures. Two fully
+// populated ventures are owned by different identities. The owner of the
+// second venture acts as outsider and calls every Search, Update and Delete API
+// using the IDs of the first venture, on their own as well as mixed with the
+// IDs of the outsider's own venture. Searches must either be rejected or return
+// zero objects. Updates and deletions must be rejected. None of the calls must
+// change any data within Redis.
+//
+// Note that updates are searched using the update service, but mutated using
+// the texupd service, since neither service implements both. Users are not
+// scoped to ventures and therefore not part of this audit.
+func Test_Isolation_001(t *testing.T) {
+	var err error
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, oauth.NewInsecureOne())
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, oauth.NewInsecureTwo())
+
+	var te1 isolationTenant
+	{
+		te1 = isolationPopulate(t, cl1, "marcojelli", "m@example.com", "IBM")
+	}
+
+	var te2 isolationTenant
+	{
+		te2 = isolationPopulate(t, cl2, "disreszi", "d@example.com", "Apple")
+	}
+
+	var bef map[string]string
+	{
+		bef, err = isolationSettle(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		mut bool
+		cal func() (int, error)
+	}{
+		// Case 0 ensures that outsiders cannot search ventures of others.
+		{
+			cal: func() (int, error) {
+				i := &venture.SearchI{
+					Obj: []*venture.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"venture.venturemark.co/id": te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Venture().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 1 ensures that outsiders cannot search timelines of others.
+		{
+			cal: func() (int, error) {
+				i := &timeline.SearchI{
+					Obj: []*timeline.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"venture.venturemark.co/id": te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Timeline().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 2 ensures that outsiders cannot search updates of others.
+		{
+			cal: func() (int, error) {
+				i := &update.SearchI{
+					Obj: []*update.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Update().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 3 ensures that outsiders cannot search updates of others by
+		// claiming them to be within their own venture.
+		{
+			cal: func() (int, error) {
+				i := &update.SearchI{
+					Obj: []*update.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"venture.venturemark.co/id":  te2.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Update().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 4 ensures that outsiders cannot search messages of others.
+		{
+			cal: func() (int, error) {
+				i := &message.SearchI{
+					Obj: []*message.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"update.venturemark.co/id":   te1.upi,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Message().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 5 ensures that outsiders cannot search messages of others by
+		// claiming them to be within their own timeline.
+		{
+			cal: func() (int, error) {
+				i := &message.SearchI{
+					Obj: []*message.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te2.tii,
+								"update.venturemark.co/id":   te1.upi,
+								"venture.venturemark.co/id":  te2.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Message().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 6 ensures that outsiders cannot search invites of others.
+		{
+			cal: func() (int, error) {
+				i := &invite.SearchI{
+					Obj: []*invite.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"venture.venturemark.co/id": te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Invite().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 7 ensures that outsiders cannot search venture roles of others.
+		{
+			cal: func() (int, error) {
+				i := &role.SearchI{
+					Obj: []*role.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"resource.venturemark.co/kind": "venture",
+								"venture.venturemark.co/id":    te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Role().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 8 ensures that outsiders cannot search timeline roles of others.
+		{
+			cal: func() (int, error) {
+				i := &role.SearchI{
+					Obj: []*role.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"resource.venturemark.co/kind": "timeline",
+								"timeline.venturemark.co/id":   te1.tii,
+								"venture.venturemark.co/id":    te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Role().Search(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 9 ensures that outsiders cannot update ventures of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &venture.UpdateI{
+					Obj: []*venture.UpdateI_Obj{
+						{
+							Metadata: map[string]string{
+								"venture.venturemark.co/id": te1.vei,
+							},
+							Jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/property/name",
+									Val: to.StringP("changed"),
+								},
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Venture().Update(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 10 ensures that outsiders cannot update timelines of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &timeline.UpdateI{
+					Obj: []*timeline.UpdateI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+							Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/property/stat",
+									Val: to.StringP("active"),
+								},
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Timeline().Update(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 11 ensures that outsiders cannot update text updates of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &texupd.UpdateI{
+					Obj: []*texupd.UpdateI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"update.venturemark.co/id":   te1.upi,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+							Jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/property/text",
+									Val: to.StringP("changed"),
+								},
+							},
+						},
+					},
+				}
+
+				o, err := cl2.TexUpd().Update(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 12 ensures that outsiders cannot update messages of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &message.UpdateI{
+					Obj: []*message.UpdateI_Obj{
+						{
+							Metadata: map[string]string{
+								"message.venturemark.co/id":  te1.mei,
+								"timeline.venturemark.co/id": te1.tii,
+								"update.venturemark.co/id":   te1.upi,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+							Jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/property/text",
+									Val: to.StringP("changed"),
+								},
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Message().Update(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 13 ensures that outsiders cannot update invites of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &invite.UpdateI{
+					Obj: []*invite.UpdateI_Obj{
+						{
+							Metadata: map[string]string{
+								"invite.venturemark.co/id":  te1.ini,
+								"venture.venturemark.co/id": te1.vei,
+							},
+							Jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/property/mail",
+									Val: to.StringP("d@example.com"),
+								},
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Invite().Update(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 14 ensures that outsiders cannot update roles of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &role.UpdateI{
+					Obj: []*role.UpdateI_Obj{
+						{
+							Metadata: map[string]string{
+								"resource.venturemark.co/kind": "venture",
+								"role.venturemark.co/id":       te1.roi,
+								"venture.venturemark.co/id":    te1.vei,
+							},
+							Jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/metadata/role.venturemark.co~1kind",
+									Val: to.StringP("member"),
+								},
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Role().Update(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 15 ensures that outsiders cannot delete roles of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &role.DeleteI{
+					Obj: []*role.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"resource.venturemark.co/kind": "venture",
+								"role.venturemark.co/id":       te1.roi,
+								"venture.venturemark.co/id":    te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Role().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 16 ensures that outsiders cannot delete invites of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &invite.DeleteI{
+					Obj: []*invite.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"invite.venturemark.co/id":  te1.ini,
+								"venture.venturemark.co/id": te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Invite().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 17 ensures that outsiders cannot delete messages of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &message.DeleteI{
+					Obj: []*message.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"message.venturemark.co/id":  te1.mei,
+								"timeline.venturemark.co/id": te1.tii,
+								"update.venturemark.co/id":   te1.upi,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Message().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 18 ensures that outsiders cannot delete messages of others by
+		// claiming them to be within their own update.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &message.DeleteI{
+					Obj: []*message.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"message.venturemark.co/id":  te1.mei,
+								"timeline.venturemark.co/id": te2.tii,
+								"update.venturemark.co/id":   te2.upi,
+								"venture.venturemark.co/id":  te2.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Message().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 19 ensures that outsiders cannot delete text updates of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &texupd.DeleteI{
+					Obj: []*texupd.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"update.venturemark.co/id":   te1.upi,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.TexUpd().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 20 ensures that outsiders cannot delete timelines of others.
+		// The timeline of the first venture is archived and would otherwise be
+		// deletable.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &timeline.DeleteI{
+					Obj: []*timeline.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"venture.venturemark.co/id":  te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Timeline().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 21 ensures that outsiders cannot delete timelines of others by
+		// claiming them to be within their own venture.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &timeline.DeleteI{
+					Obj: []*timeline.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": te1.tii,
+								"venture.venturemark.co/id":  te2.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Timeline().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+		// Case 22 ensures that outsiders cannot delete ventures of others.
+		{
+			mut: true,
+			cal: func() (int, error) {
+				i := &venture.DeleteI{
+					Obj: []*venture.DeleteI_Obj{
+						{
+							Metadata: map[string]string{
+								"venture.venturemark.co/id": te1.vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl2.Venture().Delete(context.Background(), i)
+				if err != nil {
+					return 0, tracer.Mask(err)
+				}
+
+				return len(o.Obj), nil
+			},
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			n, err := tc.cal()
+			if tc.mut && err == nil {
+				t.Fatal("mutation must be rejected")
+			}
+			if !tc.mut && err == nil && n != 0 {
+				t.Fatal("search must not return objects")
+			}
+
+			aft, err := keyspace.Dump(cl1.Redigo())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(bef, aft) {
+				t.Fatal("storage must not change")
+			}
+		})
+	}
+
+	// The owner of the first venture must still find all of their data.
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": te1.tii,
+						"update.venturemark.co/id":   te1.upi,
+						"venture.venturemark.co/id":  te1.vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one message")
+		}
+		if o.Obj[0].Metadata["message.venturemark.co/id"] != te1.mei {
+			t.Fatal("id must match across actions")
+		}
+	}
+}
+
+// isolationTenant describes the IDs of a fully populated venture.
+type isolationTenant struct {
+	// vei is the venture ID.
+	vei string
+	// tii is the ID of the archived timeline within the venture.
+	tii string
+	// upi is the ID of the text update within the timeline.
+	upi string
+	// mei is the ID of the message on the text update.
+	mei string
+	// ini is the ID of the pending invite into the venture.
+	ini string
+	// roi is the ID of the owner role of the venture.
+	roi string
+}
+
+// isolationPopulate creates a user for the identity of cli and a venture owned
+// by this user. The venture gets populated with a timeline, a text update, a
+// message, an invite and a timeline role. The timeline gets archived at last,
+// which makes it deletable.
+func isolationPopulate(t *testing.T, cli *client.Client, nam string, mai string, ven string) isolationTenant {
+	var te isolationTenant
+
+	var usi string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: nam,
+						Mail: mai,
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		usi = o.Obj[0].Metadata["user.venturemark.co/id"]
+	}
+
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: ven,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		te.vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    te.vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one role")
+		}
+
+		te.roi = o.Obj[0].Metadata["role.venturemark.co/id"]
+	}
+
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": te.vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		te.tii = o.Obj[0].Metadata["timeline.venturemark.co/id"]
+	}
+
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "timeline",
+						"role.venturemark.co/kind":     "owner",
+						"subject.venturemark.co/id":    usi,
+						"timeline.venturemark.co/id":   te.tii,
+						"venture.venturemark.co/id":    te.vei,
+					},
+				},
+			},
+		}
+
+		_, err := cli.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": te.tii,
+						"venture.venturemark.co/id":  te.vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Head: "title",
+						Text: fmt.Sprintf("Lorem ipsum %s", ven),
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		te.upi = o.Obj[0].Metadata["update.venturemark.co/id"]
+	}
+
+	{
+		i := &message.CreateI{
+			Obj: []*message.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": te.tii,
+						"update.venturemark.co/id":   te.upi,
+						"venture.venturemark.co/id":  te.vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: fmt.Sprintf("Lorem ipsum %s", ven),
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		te.mei = o.Obj[0].Metadata["message.venturemark.co/id"]
+	}
+
+	{
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": te.vei,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: "user1@site.net",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Invite().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		te.ini = o.Obj[0].Metadata["invite.venturemark.co/id"]
+	}
+
+	{
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": te.tii,
+						"venture.venturemark.co/id":  te.vei,
+					},
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{
+							Ope: "replace",
+							Pat: "/obj/property/stat",
+							Val: to.StringP("archived"),
+						},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Update(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return te
+}
+
+// isolationSettle returns the Redis dump once it stopped changing for at least
+// two seconds. The apiworker processes tasks asynchronously, which is why the
+// storage has to settle before it can serve as the baseline of the audit.
+// Every comparison waits first, since the budget executes the first attempt
+// right away.
+func isolationSettle(cli *client.Client) (map[string]string, error) {
+	var err error
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	var dum map[string]string
+	{
+		dum, err = keyspace.Dump(cli.Redigo())
+		if err != nil {
+			return nil, tracer.Mask(err)
+		}
+	}
+
+	o := func() error {
+		time.Sleep(2 * time.Second)
+
+		cur, err := keyspace.Dump(cli.Redigo())
+		if err != nil {
+			return tracer.Mask(err)
+		}
+
+		if !reflect.DeepEqual(dum, cur) {
+			dum = cur
+			return tracer.Mask(fmt.Errorf("storage must settle"))
+		}
+
+		return nil
+	}
+
+	err = b.Execute(o)
+	if err != nil {
+		return nil, tracer.Mask(err)
+	}
+
+	return dum, nil
+}
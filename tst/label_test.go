@@ -0,0 +1,63 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/venturemark/cfm/pkg/label"
+)
+
+// Test_Label_001 ensures that every conformance test is labelled, and that
+// every labelled test exists, so that filtering by labels never misses tests.
+func Test_Label_001(t *testing.T) {
+	var err error
+
+	var pkg map[string]*ast.Package
+	{
+		fil := func(i os.FileInfo) bool {
+			return strings.HasSuffix(i.Name(), "_test.go")
+		}
+
+		pkg, err = parser.ParseDir(token.NewFileSet(), ".", fil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tes := map[string]bool{}
+	for _, p := range pkg {
+		for _, f := range p.Files {
+			for _, d := range f.Decls {
+				fun, ok := d.(*ast.FuncDecl)
+				if !ok || fun.Recv != nil {
+					continue
+				}
+
+				n := fun.Name.Name
+				if strings.HasPrefix(n, "Test_") || strings.HasPrefix(n, "Fuzz_") {
+					tes[n] = true
+				}
+			}
+		}
+	}
+
+	for n := range tes {
+		_, ok := label.Catalog[n]
+		if !ok {
+			t.Errorf("test %s must be labelled", n)
+		}
+	}
+
+	for n := range label.Catalog {
+		if !tes[n] {
+			t.Errorf("labelled test %s must exist", n)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xh3b4sd/tracer"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/label"
+	"github.com/venturemark/cfm/pkg/telemetry"
+)
+
+var (
+	cmpt = flag.String("compat", "", "Directory of serialized apigengo descriptors checked for backward compatibility.")
+	lbls = flag.String("labels", "", "Labels selecting the tests to run, e.g. resource=invite,destructive=false.")
+	mtsv = flag.String("metrics-apiserver", "127.0.0.1:8081", "Address of the metrics endpoint of the apiserver.")
+	mtwk = flag.String("metrics-apiworker", "127.0.0.1:8082", "Address of the metrics endpoint of the apiworker.")
+	ndst = flag.Bool("nondestructive", false, "Whether to run against a shared environment without touching Redis.")
+	otlp = flag.String("otlp", "", "Address of the OTLP collector receiving traces, e.g. 127.0.0.1:4317.")
+	spns = flag.String("spans", "", "Path of the file spans are written to as JSON lines.")
+	wait = flag.Duration("wait", 0, "Duration to wait for the apiserver and Redis to become ready before running any test.")
+)
+
+var (
+	tel *telemetry.Telemetry
+
+	mutex sync.Mutex
+	nears []client.Near
+	spans = map[string]trace.Span{}
+)
+
+// TestMain sets up the telemetry emitting one trace per test. Tracing is
+// configured using the following flags.
+//
+//	go test ./tst/... -tags conformance -args -otlp 127.0.0.1:4317 -spans spans.json
+//
+// Tests are optionally selected by their labels, as described by label.Parse.
+// Label selection is combined with -run, if given.
+//
+//	go test ./tst/... -tags conformance -run Test_Invite -args -labels category=negative
+//
+// Tests optionally run in non destructive mode against shared environments,
+// e.g. in order to verify a deployment. Destructive tests are skipped then.
+// Redis is neither purged nor checked for being empty, and every resource
+// created by a test is deleted via the API once the test completed.
+//
+//	go test ./tst/... -tags conformance -args -nondestructive
+//
+// RPCs which took at least 80% of their deadline are reported once all tests
+// completed.
+//
+// TestMain optionally waits for the apiserver and Redis to become ready, which
+// allows to run the tests right after starting the daemons.
+//
+//	go test ./tst/... -tags conformance -args -wait 60s
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	var err error
+
+	if *lbls != "" || *ndst {
+		err = labels(*lbls, *ndst)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	if *wait != 0 {
+		c := client.Config{
+			Nondestructive: *ndst,
+			Wait:           *wait,
+		}
+
+		cli, err := client.New(c)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		err = cli.Close()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	{
+		c := telemetry.Config{
+			Endpoint: *otlp,
+			File:     *spns,
+		}
+
+		tel, err = telemetry.New(c)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	cod := m.Run()
+
+	for _, n := range nears {
+		fmt.Printf("%s took %s of its %s deadline\n", n.Method, n.Duration, n.Deadline)
+	}
+
+	err = tel.Shutdown()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	os.Exit(cod)
+}
+
+// labels restricts the tests to run to the tests selected by the given
+// labels, by rewriting the -run flag. Destructive tests are deselected if nds
+// is true. Tests already deselected by -run stay deselected.
+func labels(s string, nds bool) error {
+	f, err := label.Parse(s)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	if nds {
+		for k, v := range label.Nondestructive() {
+			f[k] = v
+		}
+	}
+
+	run := flag.Lookup("test.run").Value.String()
+
+	var top string
+	var sub string
+	{
+		spl := strings.SplitN(run, "/", 2)
+
+		top = spl[0]
+		if len(spl) == 2 {
+			sub = "/" + spl[1]
+		}
+	}
+
+	var exp *regexp.Regexp
+	{
+		exp, err = regexp.Compile(top)
+		if err != nil {
+			return tracer.Mask(err)
+		}
+	}
+
+	var lis []string
+	for _, n := range f.Tests() {
+		if exp.MatchString(n) {
+			lis = append(lis, n)
+		}
+	}
+
+	err = flag.Set("test.run", label.Pattern(lis)+sub)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// empty reports whether Redis is empty. Redis is not accessed in non
+// destructive mode, where storage is reported to be empty, since the
+// resources created by the test get deleted only once the test completed.
+func empty(cli *client.Client) (bool, error) {
+	if *ndst {
+		return true, nil
+	}
+
+	emp, err := cli.Redigo().Empty()
+	if err != nil {
+		return false, tracer.Mask(err)
+	}
+
+	return emp, nil
+}
+
+// newClient returns the client of the given test, authenticated using cre. The
+// default credentials are used if cre is nil. Every client of a test uses the
+// name of the test as correlation ID and the root span of the test, and gets
+// closed once the test completed.
+func newClient(tb testing.TB, cre credentials.PerRPCCredentials) *client.Client {
+	c := client.Config{
+		Correlation:    tb.Name(),
+		Credentials:    cre,
+		Logger:         tb,
+		Near:           near,
+		Nondestructive: *ndst,
+		Span:           span(tb),
+	}
+
+	cli, err := client.New(c)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	cleanup(tb, cli)
+
+	return cli
+}
+
+// cleanup closes cli once the given test completed. The test fails if closing
+// reports an error, e.g. because of RPCs still in flight or resources which
+// could not be deleted in non destructive mode.
+func cleanup(tb testing.TB, cli *client.Client) {
+	tb.Cleanup(func() {
+		err := cli.Close()
+		if err != nil {
+			tb.Error(err)
+		}
+	})
+}
+
+// purge wipes Redis before a test runs. Redis is not accessed in non
+// destructive mode, where every resource created by the test gets deleted via
+// the API instead, once the client of the test got closed.
+func purge(cli *client.Client) error {
+	if *ndst {
+		return nil
+	}
+
+	err := cli.Redigo().Purge()
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// near records RPCs which came near their deadline, so that slow endpoints are
+// reported once all tests completed.
+func near(n client.Near) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	nears = append(nears, n)
+}
+
+// span returns the root span of the given test. All clients of a test share
+// the same span, which ends once the test completed.
+func span(tb testing.TB) trace.Span {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	s, ok := spans[tb.Name()]
+	if !ok {
+		s = tel.Start(tb.Name())
+		spans[tb.Name()] = s
+
+		tb.Cleanup(func() {
+			s.End()
+		})
+	}
+
+	return s
+}
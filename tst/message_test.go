@@ -1,18 +1,27 @@
+//go:build conformance
 // +build conformance
 
 package tst
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/venturemark/apigengo/pkg/pbf/message"
 	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
 	"github.com/venturemark/apigengo/pkg/pbf/timeline"
 	"github.com/venturemark/apigengo/pkg/pbf/user"
 	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/keyspace"
 	"github.com/venturemark/cfm/pkg/oauth"
 	"github.com/venturemark/cfm/pkg/to"
 )
@@ -31,40 +40,22 @@ func Test_Message_001(t *testing.T) {
 
 	var cl1 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr1,
-		}
-
-		cl1, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl1 = newClient(t, cr1)
 
-		err = cl1.Redigo().Purge()
+		err = purge(cl1)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl1.Grpc().Close()
 	}
 
 	var cl2 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr2,
-		}
-
-		cl2, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl2 = newClient(t, cr2)
 
-		err = cl2.Redigo().Purge()
+		err = purge(cl2)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl2.Grpc().Close()
 	}
 
 	var us1 string
@@ -202,6 +193,36 @@ func Test_Message_001(t *testing.T) {
 		}
 	}
 
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Head: "title",
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
 	var me1 string
 	{
 		i := &message.CreateI{
@@ -209,7 +230,7 @@ func Test_Message_001(t *testing.T) {
 				{
 					Metadata: map[string]string{
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 					Property: &message.CreateI_Obj_Property{
@@ -239,7 +260,7 @@ func Test_Message_001(t *testing.T) {
 				{
 					Metadata: map[string]string{
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 					Property: &message.CreateI_Obj_Property{
@@ -268,7 +289,7 @@ func Test_Message_001(t *testing.T) {
 				{
 					Metadata: map[string]string{
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 				},
@@ -338,7 +359,7 @@ func Test_Message_001(t *testing.T) {
 					Metadata: map[string]string{
 						"message.venturemark.co/id":  me1,
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 					Jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
@@ -386,7 +407,7 @@ func Test_Message_001(t *testing.T) {
 				{
 					Metadata: map[string]string{
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 				},
@@ -436,7 +457,7 @@ func Test_Message_001(t *testing.T) {
 					Metadata: map[string]string{
 						"message.venturemark.co/id":  me1,
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 				},
@@ -478,7 +499,7 @@ func Test_Message_001(t *testing.T) {
 					Metadata: map[string]string{
 						"message.venturemark.co/id":  me2,
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 				},
@@ -506,7 +527,7 @@ func Test_Message_001(t *testing.T) {
 				{
 					Metadata: map[string]string{
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 				},
@@ -529,7 +550,7 @@ func Test_Message_001(t *testing.T) {
 				{
 					Metadata: map[string]string{
 						"timeline.venturemark.co/id": tii,
-						"update.venturemark.co/id":   "1",
+						"update.venturemark.co/id":   upi,
 						"venture.venturemark.co/id":  vei,
 					},
 				},
@@ -554,19 +575,12 @@ func Test_Message_002(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
-
-		cli, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cli = newClient(t, nil)
 
-		err = cli.Redigo().Purge()
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -589,3 +603,476 @@ func Test_Message_002(t *testing.T) {
 		}
 	}
 }
+
+// Test_Message_003 ensures that messages are threaded by the update they are
+// created on. Messages are created on real updates across multiple timelines
+// and ventures. Searching messages must never return messages of any other
+// update, timeline or venture, regardless of how the search scope is combined.
+func Test_Message_003(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// thr is the list of all message threads. Every thread describes one update
+	// and the messages created on it.
+	var thr []messageThread
+	for _, ven := range []string{"IBM", "Apple"} {
+		vei := messageVenture(t, cli, ven)
+
+		for _, tim := range []string{"Marketing Campaign", "Internal Project"} {
+			tii := messageTimeline(t, cli, vei, tim)
+
+			for j := 0; j < 2; j++ {
+				upi := messageUpdate(t, cli, vei, tii)
+
+				th := messageThread{
+					vei: vei,
+					tii: tii,
+					upi: upi,
+					mes: map[string]bool{},
+				}
+
+				for k := 0; k < 2; k++ {
+					i := &message.CreateI{
+						Obj: []*message.CreateI_Obj{
+							{
+								Metadata: map[string]string{
+									"timeline.venturemark.co/id": tii,
+									"update.venturemark.co/id":   upi,
+									"venture.venturemark.co/id":  vei,
+								},
+								Property: &message.CreateI_Obj_Property{
+									Text: fmt.Sprintf("%s %s %d %d", ven, tim, j, k),
+								},
+							},
+						},
+					}
+
+					o, err := cli.Message().Create(context.Background(), i)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+					if !ok {
+						t.Fatal("id must not be empty")
+					}
+
+					th.mes[s] = true
+				}
+
+				thr = append(thr, th)
+			}
+		}
+	}
+
+	// Every thread must list exactly its own messages.
+	for _, th := range thr {
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": th.tii,
+						"update.venturemark.co/id":   th.upi,
+						"venture.venturemark.co/id":  th.vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != len(th.mes) {
+			t.Fatalf("there must be %d messages", len(th.mes))
+		}
+
+		for _, x := range o.Obj {
+			if !th.mes[x.Metadata["message.venturemark.co/id"]] {
+				t.Fatal("message must belong to update")
+			}
+			if x.Metadata["update.venturemark.co/id"] != th.upi {
+				t.Fatal("id must match across actions")
+			}
+		}
+	}
+
+	// Combining the IDs of different threads must never leak messages. Any
+	// scope not describing an existing thread must either be rejected or
+	// return zero messages.
+	for _, a := range thr {
+		for _, b := range thr {
+			scp := []map[string]string{
+				{
+					"timeline.venturemark.co/id": a.tii,
+					"update.venturemark.co/id":   b.upi,
+					"venture.venturemark.co/id":  a.vei,
+				},
+				{
+					"timeline.venturemark.co/id": b.tii,
+					"update.venturemark.co/id":   b.upi,
+					"venture.venturemark.co/id":  a.vei,
+				},
+			}
+
+			for _, met := range scp {
+				var exp map[string]bool
+				for _, th := range thr {
+					if th.vei == met["venture.venturemark.co/id"] && th.tii == met["timeline.venturemark.co/id"] && th.upi == met["update.venturemark.co/id"] {
+						exp = th.mes
+					}
+				}
+
+				i := &message.SearchI{
+					Obj: []*message.SearchI_Obj{
+						{
+							Metadata: met,
+						},
+					},
+				}
+
+				o, err := cli.Message().Search(context.Background(), i)
+				if exp != nil && err != nil {
+					t.Fatal(err)
+				}
+				if err != nil {
+					continue
+				}
+
+				if len(o.Obj) != len(exp) {
+					t.Fatalf("there must be %d messages", len(exp))
+				}
+
+				for _, x := range o.Obj {
+					if !exp[x.Metadata["message.venturemark.co/id"]] {
+						t.Fatal("message must not leak across scopes")
+					}
+				}
+			}
+		}
+	}
+}
+
+// Test_Message_004 ensures that messages cannot be created on updates which do
+// not exist within the given timeline. That covers made up update IDs, updates
+// of other timelines and ventures, as well as deleted updates. Rejected
+// messages must not be persisted.
+func Test_Message_004(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ve1 string
+	var ve2 string
+	{
+		ve1 = messageVenture(t, cli, "IBM")
+		ve2 = messageVenture(t, cli, "Apple")
+	}
+
+	var ti1 string
+	var ti2 string
+	var ti3 string
+	{
+		ti1 = messageTimeline(t, cli, ve1, "Marketing Campaign")
+		ti2 = messageTimeline(t, cli, ve1, "Internal Project")
+		ti3 = messageTimeline(t, cli, ve2, "Marketing Campaign")
+	}
+
+	var up1 string
+	var up2 string
+	var up3 string
+	var up4 string
+	{
+		up1 = messageUpdate(t, cli, ve1, ti1)
+		up2 = messageUpdate(t, cli, ve1, ti2)
+		up3 = messageUpdate(t, cli, ve2, ti3)
+		up4 = messageUpdate(t, cli, ve1, ti1)
+	}
+
+	{
+		i := &texupd.DeleteI{
+			Obj: []*texupd.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti1,
+						"update.venturemark.co/id":   up4,
+						"venture.venturemark.co/id":  ve1,
+					},
+				},
+			},
+		}
+
+		_, err := cli.TexUpd().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		met map[string]string
+	}{
+		// Case 0 ensures that messages on made up updates are rejected.
+		{
+			met: map[string]string{
+				"timeline.venturemark.co/id": ti1,
+				"update.venturemark.co/id":   "1",
+				"venture.venturemark.co/id":  ve1,
+			},
+		},
+		// Case 1 ensures that messages on updates of other timelines within the
+		// same venture are rejected.
+		{
+			met: map[string]string{
+				"timeline.venturemark.co/id": ti1,
+				"update.venturemark.co/id":   up2,
+				"venture.venturemark.co/id":  ve1,
+			},
+		},
+		// Case 2 ensures that messages on updates of other ventures are
+		// rejected.
+		{
+			met: map[string]string{
+				"timeline.venturemark.co/id": ti1,
+				"update.venturemark.co/id":   up3,
+				"venture.venturemark.co/id":  ve1,
+			},
+		},
+		// Case 3 ensures that messages on updates of timelines claimed to be
+		// within another venture are rejected.
+		{
+			met: map[string]string{
+				"timeline.venturemark.co/id": ti3,
+				"update.venturemark.co/id":   up3,
+				"venture.venturemark.co/id":  ve1,
+			},
+		},
+		// Case 4 ensures that messages on deleted updates are rejected.
+		{
+			met: map[string]string{
+				"timeline.venturemark.co/id": ti1,
+				"update.venturemark.co/id":   up4,
+				"venture.venturemark.co/id":  ve1,
+			},
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			var bef []string
+			{
+				bef, err = keyspace.Search(cli.Redigo())
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			{
+				i := &message.CreateI{
+					Obj: []*message.CreateI_Obj{
+						{
+							Metadata: tc.met,
+							Property: &message.CreateI_Obj_Property{
+								Text: "Lorem ipsum",
+							},
+						},
+					},
+				}
+
+				_, err := cli.Message().Create(context.Background(), i)
+				if status.Code(tracer.Cause(err)) != codes.NotFound {
+					t.Fatalf("expected %s got %s", codes.NotFound, status.Code(tracer.Cause(err)))
+				}
+			}
+
+			{
+				aft, err := keyspace.Search(cli.Redigo())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(bef, aft) {
+					t.Fatal("keyspace must not change")
+				}
+			}
+		})
+	}
+
+	// The existing updates must not have received any message.
+	for _, met := range []map[string]string{
+		{
+			"timeline.venturemark.co/id": ti1,
+			"update.venturemark.co/id":   up1,
+			"venture.venturemark.co/id":  ve1,
+		},
+		{
+			"timeline.venturemark.co/id": ti2,
+			"update.venturemark.co/id":   up2,
+			"venture.venturemark.co/id":  ve1,
+		},
+		{
+			"timeline.venturemark.co/id": ti3,
+			"update.venturemark.co/id":   up3,
+			"venture.venturemark.co/id":  ve2,
+		},
+	} {
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: met,
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 0 {
+			t.Fatal("there must be zero messages")
+		}
+	}
+}
+
+// messageThread describes a single update and the IDs of the messages created
+// on it.
+type messageThread struct {
+	vei string
+	tii string
+	upi string
+	mes map[string]bool
+}
+
+func messageTimeline(t *testing.T, cli *client.Client, vei string, nam string) string {
+	i := &timeline.CreateI{
+		Obj: []*timeline.CreateI_Obj{
+			{
+				Metadata: map[string]string{
+					"venture.venturemark.co/id": vei,
+				},
+				Property: &timeline.CreateI_Obj_Property{
+					Name: nam,
+				},
+			},
+		},
+	}
+
+	o, err := cli.Timeline().Create(context.Background(), i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+	if !ok {
+		t.Fatal("id must not be empty")
+	}
+
+	return s
+}
+
+func messageUpdate(t *testing.T, cli *client.Client, vei string, tii string) string {
+	i := &texupd.CreateI{
+		Obj: []*texupd.CreateI_Obj{
+			{
+				Metadata: map[string]string{
+					"timeline.venturemark.co/id": tii,
+					"venture.venturemark.co/id":  vei,
+				},
+				Property: &texupd.CreateI_Obj_Property{
+					Head: "title",
+					Text: "Lorem ipsum",
+				},
+			},
+		},
+	}
+
+	o, err := cli.TexUpd().Create(context.Background(), i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+	if !ok {
+		t.Fatal("id must not be empty")
+	}
+
+	return s
+}
+
+func messageVenture(t *testing.T, cli *client.Client, nam string) string {
+	i := &venture.CreateI{
+		Obj: []*venture.CreateI_Obj{
+			{
+				Property: &venture.CreateI_Obj_Property{
+					Name: nam,
+				},
+			},
+		},
+	}
+
+	o, err := cli.Venture().Create(context.Background(), i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+	if !ok {
+		t.Fatal("id must not be empty")
+	}
+
+	return s
+}
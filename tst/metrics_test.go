@@ -0,0 +1,303 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"github.com/xh3b4sd/budget"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/metrics"
+)
+
+const (
+	// metricsRequests is the counter of handled gRPC requests exposed by the
+	// apiserver, labelled by grpc_service, grpc_method and grpc_code.
+	metricsRequests = "grpc_server_handled_total"
+	// metricsTaskErrors is the counter of failed tasks exposed by the
+	// apiworker.
+	metricsTaskErrors = "apiworker_task_errors_total"
+)
+
+// Test_Metrics_001 ensures that the apiserver counts every handled request
+// exactly once.
+func Test_Metrics_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var scr *metrics.Scraper
+	{
+		c := metrics.Config{
+			Address: *mtsv,
+		}
+
+		scr, err = metrics.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var bef metrics.Snapshot
+	{
+		bef, err = scr.Scrape()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	{
+		aft, err := scr.Scrape()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = metrics.Exists(aft, metricsRequests)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lab := map[string]string{
+			"grpc_method":  "Create",
+			"grpc_service": "venture.API",
+		}
+
+		err = metrics.Delta(bef, aft, 1, metricsRequests, lab)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = metrics.Delta(bef, aft, 0, metricsRequests, map[string]string{"grpc_service": "timeline.API"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		_, err := cli.Venture().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Test_Metrics_002 ensures that the apiworker does not record any task errors
+// while processing the deletion of a venture. The test waits for the storage
+// to become empty, which cannot be observed in non destructive mode.
+func Test_Metrics_002(t *testing.T) {
+	if *ndst {
+		t.Skip("storage cannot be observed in non destructive mode")
+	}
+
+	var err error
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var scr *metrics.Scraper
+	{
+		c := metrics.Config{
+			Address: *mtwk,
+		}
+
+		scr, err = metrics.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var bef metrics.Snapshot
+	{
+		bef, err = scr.Scrape()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		_, err := cli.Venture().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		_, err := cli.User().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The apiworker cleans up asynchronously. Once the storage is empty all
+	// tasks got processed and their errors, if any, got recorded.
+	{
+		o := func() error {
+			emp, err := empty(cli)
+			if err != nil {
+				return tracer.Mask(err)
+			}
+
+			if !emp {
+				return tracer.Mask(fmt.Errorf("storage must be empty"))
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		aft, err := scr.Scrape()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = metrics.Exists(aft, metricsTaskErrors)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = metrics.Delta(bef, aft, 0, metricsTaskErrors, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
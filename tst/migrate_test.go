@@ -0,0 +1,77 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"testing"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/migrate"
+	"github.com/venturemark/cfm/pkg/seed"
+)
+
+// Test_Migrate_001 ensures that data seeded against the apiserver verifies
+// against the very same apiserver without any change of the Redis keyspace.
+// This is the baseline of every upgrade verified using "cfm migrate".
+func Test_Migrate_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var mig *migrate.Migrate
+	{
+		c := migrate.Config{
+			Seed: seed.Config{
+				Invites:   1,
+				Messages:  2,
+				Roles:     1,
+				Seed:      3,
+				Timelines: 2,
+				Updates:   2,
+				Users:     2,
+				Ventures:  2,
+			},
+		}
+
+		mig, err = migrate.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sta *migrate.State
+	{
+		sta, err = mig.Seed(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(sta.Archives) != 4 {
+			t.Fatal("there must be four archives")
+		}
+		if len(sta.Keyspace) == 0 {
+			t.Fatal("keyspace must not be empty")
+		}
+	}
+
+	{
+		rep, err := mig.Verify(context.Background(), sta)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(rep.Added) != 0 || len(rep.Changed) != 0 || len(rep.Removed) != 0 {
+			t.Fatalf("keyspace must not change: %#v", rep)
+		}
+	}
+}
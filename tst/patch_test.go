@@ -0,0 +1,1782 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/update"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// The tests in this file send JSON patches to every Update endpoint. The
+// conformance rules verified here are as follows.
+//
+//     * add, remove, replace and test are accepted on mutable properties.
+//     * move and copy are rejected, since the jsnpatch messages do not carry
+//       the from field those operations require.
+//     * A failing test operation rejects the whole patch, so that no other
+//       operation of the same patch is applied.
+//     * Array indices must address existing elements and must not have leading
+//       zeros, be negative or use "-".
+//     * Path segments must be escaped using ~0 and ~1. Unknown escapes and
+//       unescaped slashes within metadata keys are rejected.
+//     * Metadata like resource IDs is read-only.
+//
+// Rejected patches must be answered with codes.InvalidArgument.
+
+// Test_Patch_001 ensures that JSON patches sent to the timeline Update
+// endpoint are accepted or rejected as specified.
+func Test_Patch_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	testCases := []struct {
+		jsnpatch []*timeline.UpdateI_Obj_Jsnpatch
+		accepted bool
+	}{
+		// Case 0 ensures that replace is accepted.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/desc", Val: to.StringP("Lorem")},
+			},
+			accepted: true,
+		},
+		// Case 1 ensures that add is accepted.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "add", Pat: "/obj/property/desc", Val: to.StringP("Ipsum")},
+			},
+			accepted: true,
+		},
+		// Case 2 ensures that remove is accepted.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "remove", Pat: "/obj/property/desc"},
+			},
+			accepted: true,
+		},
+		// Case 3 ensures that a succeeding test is accepted.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/name", Val: to.StringP("Marketing Campaign")},
+			},
+			accepted: true,
+		},
+		// Case 4 ensures that a failing test is rejected.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/name", Val: to.StringP("wrong")},
+			},
+			accepted: false,
+		},
+		// Case 5 ensures that move is rejected.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "move", Pat: "/obj/property/desc", Val: to.StringP("/obj/property/name")},
+			},
+			accepted: false,
+		},
+		// Case 6 ensures that copy is rejected.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "copy", Pat: "/obj/property/desc", Val: to.StringP("/obj/property/name")},
+			},
+			accepted: false,
+		},
+		// Case 7 ensures that a multi-op patch guarded by a succeeding test is
+		// accepted.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/name", Val: to.StringP("Marketing Campaign")},
+				{Ope: "replace", Pat: "/obj/property/desc", Val: to.StringP("Dolor")},
+			},
+			accepted: true,
+		},
+		// Case 8 ensures that a multi-op patch with a failing test is rejected
+		// as a whole.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/desc", Val: to.StringP("Sit")},
+				{Ope: "test", Pat: "/obj/property/name", Val: to.StringP("wrong")},
+			},
+			accepted: false,
+		},
+		// Case 9 ensures that ~0 is decoded and does not match any property.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/na~0me", Val: to.StringP("Lorem")},
+			},
+			accepted: false,
+		},
+		// Case 10 ensures that unknown escapes are rejected.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/desc~2", Val: to.StringP("Lorem")},
+			},
+			accepted: false,
+		},
+		// Case 11 ensures that the timeline ID is read-only.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/timeline.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 12 ensures that the venture ID is read-only.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/venture.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 13 ensures that unescaped metadata keys are rejected.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/timeline.venturemark.co/id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 14 ensures that the whole document cannot be replaced.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "", Val: to.StringP("{}")},
+			},
+			accepted: false,
+		},
+		// Case 15 ensures that unknown operations are rejected.
+		{
+			jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+				{Ope: "merge", Pat: "/obj/property/desc", Val: to.StringP("Lorem")},
+			},
+			accepted: false,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			i := &timeline.UpdateI{
+				Obj: []*timeline.UpdateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+						Jsnpatch: tc.jsnpatch,
+					},
+				},
+			}
+
+			o, err := cli.Timeline().Update(context.Background(), i)
+			if tc.accepted {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["timeline.venturemark.co/status"]
+				if !ok {
+					t.Fatal("status must not be empty")
+				}
+				if s != "updated" {
+					t.Fatal("status must be updated")
+				}
+			} else {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+				}
+			}
+		})
+	}
+
+	{
+		i := &timeline.SearchI{
+			Obj: []*timeline.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one timeline")
+		}
+
+		if o.Obj[0].Metadata["timeline.venturemark.co/id"] != tii {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Property.Desc != "Dolor" {
+			t.Fatal("desc must be Dolor")
+		}
+		if o.Obj[0].Property.Name != "Marketing Campaign" {
+			t.Fatal("name must be Marketing Campaign")
+		}
+	}
+}
+
+// Test_Patch_002 ensures that JSON patches sent to the text update Update
+// endpoint are accepted or rejected as specified.
+func Test_Patch_002(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Head: "title",
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
+	testCases := []struct {
+		jsnpatch []*texupd.UpdateI_Obj_Jsnpatch
+		accepted bool
+	}{
+		// Case 0 ensures that replace is accepted.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("changed")},
+			},
+			accepted: true,
+		},
+		// Case 1 ensures that remove is accepted.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "remove", Pat: "/obj/property/head"},
+			},
+			accepted: true,
+		},
+		// Case 2 ensures that add is accepted.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "add", Pat: "/obj/property/head", Val: to.StringP("headline")},
+			},
+			accepted: true,
+		},
+		// Case 3 ensures that a succeeding test is accepted.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("changed")},
+			},
+			accepted: true,
+		},
+		// Case 4 ensures that a failing test is rejected.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("wrong")},
+			},
+			accepted: false,
+		},
+		// Case 5 ensures that move is rejected.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "move", Pat: "/obj/property/head", Val: to.StringP("/obj/property/text")},
+			},
+			accepted: false,
+		},
+		// Case 6 ensures that copy is rejected.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "copy", Pat: "/obj/property/head", Val: to.StringP("/obj/property/text")},
+			},
+			accepted: false,
+		},
+		// Case 7 ensures that a multi-op patch is applied in order.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("first")},
+				{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("first")},
+				{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("second")},
+			},
+			accepted: true,
+		},
+		// Case 8 ensures that a multi-op patch with a failing test is rejected
+		// as a whole.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("third")},
+				{Ope: "test", Pat: "/obj/property/head", Val: to.StringP("wrong")},
+			},
+			accepted: false,
+		},
+		// Case 9 ensures that the update ID is read-only.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/update.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 10 ensures that the timeline ID is read-only.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/timeline.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 11 ensures that metadata cannot be removed.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "remove", Pat: "/obj/metadata/venture.venturemark.co~1id"},
+			},
+			accepted: false,
+		},
+		// Case 12 ensures that unknown properties are rejected.
+		{
+			jsnpatch: []*texupd.UpdateI_Obj_Jsnpatch{
+				{Ope: "add", Pat: "/obj/property/unknown", Val: to.StringP("Lorem")},
+			},
+			accepted: false,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			i := &texupd.UpdateI{
+				Obj: []*texupd.UpdateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   upi,
+							"venture.venturemark.co/id":  vei,
+						},
+						Jsnpatch: tc.jsnpatch,
+					},
+				},
+			}
+
+			o, err := cli.TexUpd().Update(context.Background(), i)
+			if tc.accepted {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["update.venturemark.co/status"]
+				if !ok {
+					t.Fatal("status must not be empty")
+				}
+				if s != "updated" {
+					t.Fatal("status must be updated")
+				}
+			} else {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+				}
+			}
+		})
+	}
+
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one update")
+		}
+
+		if o.Obj[0].Metadata["update.venturemark.co/id"] != upi {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Property.Head != "headline" {
+			t.Fatal("head must be headline")
+		}
+		if o.Obj[0].Property.Text != "second" {
+			t.Fatal("text must be second")
+		}
+	}
+}
+
+// Test_Patch_003 ensures that JSON patches sent to the message Update endpoint
+// are accepted or rejected as specified.
+func Test_Patch_003(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
+	var mei string
+	{
+		i := &message.CreateI{
+			Obj: []*message.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		mei = s
+	}
+
+	testCases := []struct {
+		jsnpatch []*message.UpdateI_Obj_Jsnpatch
+		accepted bool
+	}{
+		// Case 0 ensures that replace is accepted.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("changed")},
+			},
+			accepted: true,
+		},
+		// Case 1 ensures that a succeeding test is accepted.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("changed")},
+			},
+			accepted: true,
+		},
+		// Case 2 ensures that a failing test is rejected.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("wrong")},
+			},
+			accepted: false,
+		},
+		// Case 3 ensures that move is rejected.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "move", Pat: "/obj/property/reid", Val: to.StringP("/obj/property/text")},
+			},
+			accepted: false,
+		},
+		// Case 4 ensures that copy is rejected.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "copy", Pat: "/obj/property/reid", Val: to.StringP("/obj/property/text")},
+			},
+			accepted: false,
+		},
+		// Case 5 ensures that a multi-op patch guarded by a succeeding test is
+		// accepted.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("changed")},
+				{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("again")},
+			},
+			accepted: true,
+		},
+		// Case 6 ensures that a multi-op patch with a failing test is rejected
+		// as a whole.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/text", Val: to.StringP("never")},
+				{Ope: "test", Pat: "/obj/property/text", Val: to.StringP("wrong")},
+			},
+			accepted: false,
+		},
+		// Case 7 ensures that the message ID is read-only.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/message.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 8 ensures that the update ID is read-only.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/update.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 9 ensures that the message author is read-only.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/user.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 10 ensures that unknown escapes are rejected.
+		{
+			jsnpatch: []*message.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/te~xt", Val: to.StringP("Lorem")},
+			},
+			accepted: false,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			i := &message.UpdateI{
+				Obj: []*message.UpdateI_Obj{
+					{
+						Metadata: map[string]string{
+							"message.venturemark.co/id":  mei,
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   upi,
+							"venture.venturemark.co/id":  vei,
+						},
+						Jsnpatch: tc.jsnpatch,
+					},
+				},
+			}
+
+			o, err := cli.Message().Update(context.Background(), i)
+			if tc.accepted {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["message.venturemark.co/status"]
+				if !ok {
+					t.Fatal("status must not be empty")
+				}
+				if s != "updated" {
+					t.Fatal("status must be updated")
+				}
+			} else {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+				}
+			}
+		})
+	}
+
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one message")
+		}
+
+		if o.Obj[0].Metadata["message.venturemark.co/id"] != mei {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Property.Text != "again" {
+			t.Fatal("text must be again")
+		}
+	}
+}
+
+// Test_Patch_004 ensures that JSON patches sent to the role Update endpoint are
+// accepted or rejected as specified.
+func Test_Patch_004(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var roi string
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    "2",
+						"venture.venturemark.co/id":    "1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["role.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		roi = s
+	}
+
+	testCases := []struct {
+		jsnpatch []*role.UpdateI_Obj_Jsnpatch
+		accepted bool
+	}{
+		// Case 0 ensures that replacing the escaped role kind is accepted.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/role.venturemark.co~1kind", Val: to.StringP("owner")},
+			},
+			accepted: true,
+		},
+		// Case 1 ensures that unescaped metadata keys are rejected.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/role.venturemark.co/kind", Val: to.StringP("member")},
+			},
+			accepted: false,
+		},
+		// Case 2 ensures that ~0 is decoded and does not match any metadata key.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/role.venturemark.co~0kind", Val: to.StringP("member")},
+			},
+			accepted: false,
+		},
+		// Case 3 ensures that unknown role kinds are rejected.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/role.venturemark.co~1kind", Val: to.StringP("garbage")},
+			},
+			accepted: false,
+		},
+		// Case 4 ensures that the role ID is read-only.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/role.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 5 ensures that the subject ID is read-only.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/subject.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 6 ensures that a succeeding test is accepted.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/metadata/role.venturemark.co~1kind", Val: to.StringP("owner")},
+			},
+			accepted: true,
+		},
+		// Case 7 ensures that the role kind cannot be removed.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "remove", Pat: "/obj/metadata/role.venturemark.co~1kind"},
+			},
+			accepted: false,
+		},
+		// Case 8 ensures that move is rejected.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "move", Pat: "/obj/metadata/role.venturemark.co~1kind", Val: to.StringP("/obj/metadata/subject.venturemark.co~1id")},
+			},
+			accepted: false,
+		},
+		// Case 9 ensures that copy is rejected.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "copy", Pat: "/obj/metadata/role.venturemark.co~1kind", Val: to.StringP("/obj/metadata/subject.venturemark.co~1id")},
+			},
+			accepted: false,
+		},
+		// Case 10 ensures that a multi-op patch with a failing test is rejected
+		// as a whole.
+		{
+			jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/role.venturemark.co~1kind", Val: to.StringP("member")},
+				{Ope: "test", Pat: "/obj/metadata/subject.venturemark.co~1id", Val: to.StringP("wrong")},
+			},
+			accepted: false,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			i := &role.UpdateI{
+				Obj: []*role.UpdateI_Obj{
+					{
+						Metadata: map[string]string{
+							"resource.venturemark.co/kind": "venture",
+							"role.venturemark.co/id":       roi,
+							"venture.venturemark.co/id":    "1",
+						},
+						Jsnpatch: tc.jsnpatch,
+					},
+				},
+			}
+
+			o, err := cli.Role().Update(context.Background(), i)
+			if tc.accepted {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["role.venturemark.co/status"]
+				if !ok {
+					t.Fatal("status must not be empty")
+				}
+				if s != "updated" {
+					t.Fatal("status must be updated")
+				}
+			} else {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+				}
+			}
+		})
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    "1",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one role")
+		}
+
+		if o.Obj[0].Metadata["role.venturemark.co/id"] != roi {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Metadata["role.venturemark.co/kind"] != "owner" {
+			t.Fatal("kind must be owner")
+		}
+		if o.Obj[0].Metadata["subject.venturemark.co/id"] != "2" {
+			t.Fatal("id must match across actions")
+		}
+	}
+}
+
+// Test_Patch_005 ensures that JSON patches sent to the user Update endpoint are
+// accepted or rejected as specified, including array index edge cases.
+func Test_Patch_005(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var usi string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+						Prof: []*user.CreateI_Obj_Property_Prof{
+							{
+								Desc: "Founder",
+								Vent: "Venturemark",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		usi = s
+	}
+
+	testCases := []struct {
+		jsnpatch []*user.UpdateI_Obj_Jsnpatch
+		accepted bool
+	}{
+		// Case 0 ensures that replacing an existing array element is accepted.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/prof/0/desc", Val: to.StringP("CEO")},
+			},
+			accepted: true,
+		},
+		// Case 1 ensures that array indices out of range are rejected.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/prof/1/desc", Val: to.StringP("CTO")},
+			},
+			accepted: false,
+		},
+		// Case 2 ensures that the end of array marker is rejected for replace.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/prof/-/desc", Val: to.StringP("CTO")},
+			},
+			accepted: false,
+		},
+		// Case 3 ensures that array indices with leading zeros are rejected.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/prof/00/desc", Val: to.StringP("CTO")},
+			},
+			accepted: false,
+		},
+		// Case 4 ensures that negative array indices are rejected.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/prof/-1/desc", Val: to.StringP("CTO")},
+			},
+			accepted: false,
+		},
+		// Case 5 ensures that non numeric array indices are rejected.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/prof/x/desc", Val: to.StringP("CTO")},
+			},
+			accepted: false,
+		},
+		// Case 6 ensures that a succeeding test within an array is accepted.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/prof/0/vent", Val: to.StringP("Venturemark")},
+			},
+			accepted: true,
+		},
+		// Case 7 ensures that replacing a scalar property is accepted.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/name", Val: to.StringP("marco")},
+			},
+			accepted: true,
+		},
+		// Case 8 ensures that move is rejected.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "move", Pat: "/obj/property/desc", Val: to.StringP("/obj/property/name")},
+			},
+			accepted: false,
+		},
+		// Case 9 ensures that copy is rejected.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "copy", Pat: "/obj/property/prof/0/vent", Val: to.StringP("/obj/property/prof/0/desc")},
+			},
+			accepted: false,
+		},
+		// Case 10 ensures that a multi-op patch with a failing test is rejected
+		// as a whole.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/name", Val: to.StringP("never")},
+				{Ope: "test", Pat: "/obj/property/prof/0/desc", Val: to.StringP("Founder")},
+			},
+			accepted: false,
+		},
+		// Case 11 ensures that the user ID is read-only.
+		{
+			jsnpatch: []*user.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/user.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			i := &user.UpdateI{
+				Obj: []*user.UpdateI_Obj{
+					{
+						Metadata: map[string]string{
+							"user.venturemark.co/id": usi,
+						},
+						Jsnpatch: tc.jsnpatch,
+					},
+				},
+			}
+
+			o, err := cli.User().Update(context.Background(), i)
+			if tc.accepted {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["user.venturemark.co/status"]
+				if !ok {
+					t.Fatal("status must not be empty")
+				}
+				if s != "updated" {
+					t.Fatal("status must be updated")
+				}
+			} else {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+				}
+			}
+		})
+	}
+
+	{
+		i := &user.SearchI{
+			Obj: []*user.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"user.venturemark.co/id": usi,
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one user")
+		}
+
+		if o.Obj[0].Property.Name != "marco" {
+			t.Fatal("name must be marco")
+		}
+		if len(o.Obj[0].Property.Prof) != 1 {
+			t.Fatal("there must be one profile")
+		}
+		if o.Obj[0].Property.Prof[0].Desc != "CEO" {
+			t.Fatal("desc must be CEO")
+		}
+		if o.Obj[0].Property.Prof[0].Vent != "Venturemark" {
+			t.Fatal("vent must be Venturemark")
+		}
+	}
+}
+
+// Test_Patch_006 ensures that JSON patches sent to the venture Update endpoint
+// are accepted or rejected as specified, including array index edge cases.
+func Test_Patch_006(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+						Link: []*venture.CreateI_Obj_Property_Link{
+							{
+								Addr: "https://ibm.com",
+								Text: "Website",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	testCases := []struct {
+		jsnpatch []*venture.UpdateI_Obj_Jsnpatch
+		accepted bool
+	}{
+		// Case 0 ensures that replacing a scalar property is accepted.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/name", Val: to.StringP("IBM Corp")},
+			},
+			accepted: true,
+		},
+		// Case 1 ensures that add is accepted.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "add", Pat: "/obj/property/desc", Val: to.StringP("Lorem ipsum")},
+			},
+			accepted: true,
+		},
+		// Case 2 ensures that replacing an existing array element is accepted.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/link/0/text", Val: to.StringP("Homepage")},
+			},
+			accepted: true,
+		},
+		// Case 3 ensures that array indices out of range are rejected.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/link/1/text", Val: to.StringP("Blog")},
+			},
+			accepted: false,
+		},
+		// Case 4 ensures that the end of array marker is rejected for replace.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/link/-/text", Val: to.StringP("Blog")},
+			},
+			accepted: false,
+		},
+		// Case 5 ensures that a failing test is rejected.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/link/0/addr", Val: to.StringP("https://wrong.com")},
+			},
+			accepted: false,
+		},
+		// Case 6 ensures that move is rejected.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "move", Pat: "/obj/property/desc", Val: to.StringP("/obj/property/name")},
+			},
+			accepted: false,
+		},
+		// Case 7 ensures that copy is rejected.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "copy", Pat: "/obj/property/desc", Val: to.StringP("/obj/property/name")},
+			},
+			accepted: false,
+		},
+		// Case 8 ensures that the venture ID is read-only.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/venture.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 9 ensures that a multi-op patch with a failing test is rejected
+		// as a whole.
+		{
+			jsnpatch: []*venture.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/name", Val: to.StringP("never")},
+				{Ope: "test", Pat: "/obj/property/name", Val: to.StringP("IBM")},
+			},
+			accepted: false,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			i := &venture.UpdateI{
+				Obj: []*venture.UpdateI_Obj{
+					{
+						Metadata: map[string]string{
+							"venture.venturemark.co/id": vei,
+						},
+						Jsnpatch: tc.jsnpatch,
+					},
+				},
+			}
+
+			o, err := cli.Venture().Update(context.Background(), i)
+			if tc.accepted {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["venture.venturemark.co/status"]
+				if !ok {
+					t.Fatal("status must not be empty")
+				}
+				if s != "updated" {
+					t.Fatal("status must be updated")
+				}
+			} else {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+				}
+			}
+		})
+	}
+
+	{
+		i := &venture.SearchI{
+			Obj: []*venture.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one venture")
+		}
+
+		if o.Obj[0].Property.Name != "IBM Corp" {
+			t.Fatal("name must be IBM Corp")
+		}
+		if o.Obj[0].Property.Desc != "Lorem ipsum" {
+			t.Fatal("desc must be Lorem ipsum")
+		}
+		if len(o.Obj[0].Property.Link) != 1 {
+			t.Fatal("there must be one link")
+		}
+		if o.Obj[0].Property.Link[0].Text != "Homepage" {
+			t.Fatal("text must be Homepage")
+		}
+	}
+}
+
+// Test_Patch_007 ensures that JSON patches sent to the invite Update endpoint
+// are accepted or rejected as specified.
+func Test_Patch_007(t *testing.T) {
+	var err error
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var cod string
+	var ini string
+	{
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: "user2@site.net",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Invite().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			s, ok := o.Obj[0].Metadata["invite.venturemark.co/code"]
+			if !ok {
+				t.Fatal("code must not be empty")
+			}
+
+			cod = s
+		}
+
+		{
+			s, ok := o.Obj[0].Metadata["invite.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+
+			ini = s
+		}
+	}
+
+	// Note that the only accepted patch is the last test case, because
+	// accepting an invite cannot be undone and thus ends the invite's
+	// lifecycle.
+	testCases := []struct {
+		jsnpatch []*invite.UpdateI_Obj_Jsnpatch
+		accepted bool
+	}{
+		// Case 0 ensures that unknown invite states are rejected.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/stat", Val: to.StringP("garbage")},
+			},
+			accepted: false,
+		},
+		// Case 1 ensures that the invite mail cannot be changed.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/mail", Val: to.StringP("other@site.net")},
+			},
+			accepted: false,
+		},
+		// Case 2 ensures that the invite ID is read-only.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/invite.venturemark.co~1id", Val: to.StringP("1")},
+			},
+			accepted: false,
+		},
+		// Case 3 ensures that the invite code is read-only.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/metadata/invite.venturemark.co~1code", Val: to.StringP("garbage")},
+			},
+			accepted: false,
+		},
+		// Case 4 ensures that move is rejected.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "move", Pat: "/obj/property/stat", Val: to.StringP("/obj/property/mail")},
+			},
+			accepted: false,
+		},
+		// Case 5 ensures that copy is rejected.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "copy", Pat: "/obj/property/stat", Val: to.StringP("/obj/property/mail")},
+			},
+			accepted: false,
+		},
+		// Case 6 ensures that a multi-op patch with a failing test is rejected
+		// as a whole.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "replace", Pat: "/obj/property/stat", Val: to.StringP("accepted")},
+				{Ope: "test", Pat: "/obj/property/mail", Val: to.StringP("wrong@site.net")},
+			},
+			accepted: false,
+		},
+		// Case 7 ensures that a multi-op patch guarded by a succeeding test is
+		// accepted.
+		{
+			jsnpatch: []*invite.UpdateI_Obj_Jsnpatch{
+				{Ope: "test", Pat: "/obj/property/stat", Val: to.StringP("pending")},
+				{Ope: "replace", Pat: "/obj/property/stat", Val: to.StringP("accepted")},
+			},
+			accepted: true,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			i := &invite.UpdateI{
+				Obj: []*invite.UpdateI_Obj{
+					{
+						Metadata: map[string]string{
+							"invite.venturemark.co/code":   cod,
+							"invite.venturemark.co/id":     ini,
+							"resource.venturemark.co/kind": "venture",
+							"role.venturemark.co/kind":     "member",
+							"venture.venturemark.co/id":    vei,
+						},
+						Jsnpatch: tc.jsnpatch,
+					},
+				},
+			}
+
+			o, err := cl2.Invite().Update(context.Background(), i)
+			if tc.accepted {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["invite.venturemark.co/status"]
+				if !ok {
+					t.Fatal("status must not be empty")
+				}
+				if s != "updated" {
+					t.Fatal("status must be updated")
+				}
+			} else {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(err))
+				}
+			}
+		})
+	}
+
+	{
+		i := &invite.SearchI{
+			Obj: []*invite.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Invite().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one invite")
+		}
+
+		if o.Obj[0].Metadata["invite.venturemark.co/id"] != ini {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Property.Mail != "user2@site.net" {
+			t.Fatal("mail must be user2@site.net")
+		}
+		if o.Obj[0].Property.Stat != "accepted" {
+			t.Fatal("stat must be accepted")
+		}
+	}
+}
@@ -1,14 +1,22 @@
+//go:build conformance
 // +build conformance
 
 package tst
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/oauth"
 	"github.com/venturemark/cfm/pkg/to"
 )
 
@@ -19,19 +27,12 @@ func Test_Role_001(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	var su1 string
@@ -455,19 +456,12 @@ func Test_Role_002(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -489,3 +483,627 @@ func Test_Role_002(t *testing.T) {
 		}
 	}
 }
+
+// Test_Role_003 ensures that venture roles can only be changed by owners and
+// that the last owner of a venture can neither be demoted nor removed.
+func Test_Role_003(t *testing.T) {
+	var err error
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	var us1 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		us1 = o.Obj[0].Metadata["user.venturemark.co/id"]
+	}
+
+	var us2 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		us2 = o.Obj[0].Metadata["user.venturemark.co/id"]
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	var ro1 string
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one role")
+		}
+		if o.Obj[0].Metadata["subject.venturemark.co/id"] != us1 {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Metadata["role.venturemark.co/kind"] != "owner" {
+			t.Fatal("kind must be owner")
+		}
+
+		ro1 = o.Obj[0].Metadata["role.venturemark.co/id"]
+	}
+
+	var ro2 string
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    us2,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ro2 = o.Obj[0].Metadata["role.venturemark.co/id"]
+	}
+
+	// The test cases below are executed in order, since every accepted case
+	// changes the roles the following cases operate on. An empty kind means the
+	// role gets deleted.
+	testCases := []struct {
+		cli  *client.Client
+		rol  string
+		kin  string
+		code codes.Code
+	}{
+		// Case 0 ensures that members cannot promote themselves.
+		{
+			cli:  cl2,
+			rol:  ro2,
+			kin:  "owner",
+			code: codes.PermissionDenied,
+		},
+		// Case 1 ensures that members cannot demote owners.
+		{
+			cli:  cl2,
+			rol:  ro1,
+			kin:  "member",
+			code: codes.PermissionDenied,
+		},
+		// Case 2 ensures that members cannot remove owners.
+		{
+			cli:  cl2,
+			rol:  ro1,
+			kin:  "",
+			code: codes.PermissionDenied,
+		},
+		// Case 3 ensures that the last owner cannot demote themselves.
+		{
+			cli:  cl1,
+			rol:  ro1,
+			kin:  "member",
+			code: codes.FailedPrecondition,
+		},
+		// Case 4 ensures that the last owner cannot remove themselves.
+		{
+			cli:  cl1,
+			rol:  ro1,
+			kin:  "",
+			code: codes.FailedPrecondition,
+		},
+		// Case 5 ensures that owners can promote members.
+		{
+			cli:  cl1,
+			rol:  ro2,
+			kin:  "owner",
+			code: codes.OK,
+		},
+		// Case 6 ensures that owners can demote other owners.
+		{
+			cli:  cl1,
+			rol:  ro2,
+			kin:  "member",
+			code: codes.OK,
+		},
+		// Case 7 ensures that owners can promote members again.
+		{
+			cli:  cl1,
+			rol:  ro2,
+			kin:  "owner",
+			code: codes.OK,
+		},
+		// Case 8 ensures that owners can demote themselves while another owner
+		// remains.
+		{
+			cli:  cl1,
+			rol:  ro1,
+			kin:  "member",
+			code: codes.OK,
+		},
+		// Case 9 ensures that the new last owner cannot demote themselves.
+		{
+			cli:  cl2,
+			rol:  ro2,
+			kin:  "member",
+			code: codes.FailedPrecondition,
+		},
+		// Case 10 ensures that demoted owners lost their permissions.
+		{
+			cli:  cl1,
+			rol:  ro1,
+			kin:  "owner",
+			code: codes.PermissionDenied,
+		},
+		// Case 11 ensures that owners can remove members.
+		{
+			cli:  cl2,
+			rol:  ro1,
+			kin:  "",
+			code: codes.OK,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			met := map[string]string{
+				"resource.venturemark.co/kind": "venture",
+				"role.venturemark.co/id":       tc.rol,
+				"venture.venturemark.co/id":    vei,
+			}
+
+			if tc.kin == "" {
+				i := &role.DeleteI{
+					Obj: []*role.DeleteI_Obj{
+						{
+							Metadata: met,
+						},
+					},
+				}
+
+				_, err = tc.cli.Role().Delete(context.Background(), i)
+			} else {
+				i := &role.UpdateI{
+					Obj: []*role.UpdateI_Obj{
+						{
+							Metadata: met,
+							Jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/metadata/role.venturemark.co~1kind",
+									Val: to.StringP(tc.kin),
+								},
+							},
+						},
+					},
+				}
+
+				_, err = tc.cli.Role().Update(context.Background(), i)
+			}
+
+			if status.Code(err) != tc.code {
+				t.Fatalf("expected %s got %s", tc.code, status.Code(err))
+			}
+		})
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl2.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one role")
+		}
+		if o.Obj[0].Metadata["role.venturemark.co/id"] != ro2 {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Metadata["role.venturemark.co/kind"] != "owner" {
+			t.Fatal("kind must be owner")
+		}
+	}
+}
+
+// Test_Role_004 ensures that timeline roles are changed independently of
+// venture roles. Timeline roles can only be changed by venture owners. The last
+// owner protection does not apply to timeline roles, since timelines are always
+// governed by the owners of their venture.
+func Test_Role_004(t *testing.T) {
+	var err error
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var us2 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		us2 = o.Obj[0].Metadata["user.venturemark.co/id"]
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vei = o.Obj[0].Metadata["venture.venturemark.co/id"]
+	}
+
+	var rv2 string
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    us2,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rv2 = o.Obj[0].Metadata["role.venturemark.co/id"]
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tii = o.Obj[0].Metadata["timeline.venturemark.co/id"]
+	}
+
+	var rt2 string
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "timeline",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    us2,
+						"timeline.venturemark.co/id":   tii,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rt2 = o.Obj[0].Metadata["role.venturemark.co/id"]
+	}
+
+	// The test cases below are executed in order, since every accepted case
+	// changes the roles the following cases operate on. An empty kind means the
+	// role gets deleted.
+	testCases := []struct {
+		cli  *client.Client
+		kin  string
+		code codes.Code
+	}{
+		// Case 0 ensures that timeline members cannot promote themselves.
+		{
+			cli:  cl2,
+			kin:  "owner",
+			code: codes.PermissionDenied,
+		},
+		// Case 1 ensures that venture owners can promote timeline members.
+		{
+			cli:  cl1,
+			kin:  "owner",
+			code: codes.OK,
+		},
+		// Case 2 ensures that timeline owners, who are venture members, cannot
+		// change their own timeline role.
+		{
+			cli:  cl2,
+			kin:  "member",
+			code: codes.PermissionDenied,
+		},
+		// Case 3 ensures that venture owners can demote the only timeline owner.
+		{
+			cli:  cl1,
+			kin:  "member",
+			code: codes.OK,
+		},
+		// Case 4 ensures that timeline members cannot remove their timeline role.
+		{
+			cli:  cl2,
+			kin:  "",
+			code: codes.PermissionDenied,
+		},
+	}
+
+	for j, tc := range testCases {
+		t.Run(strconv.Itoa(j), func(t *testing.T) {
+			met := map[string]string{
+				"resource.venturemark.co/kind": "timeline",
+				"role.venturemark.co/id":       rt2,
+				"timeline.venturemark.co/id":   tii,
+				"venture.venturemark.co/id":    vei,
+			}
+
+			if tc.kin == "" {
+				i := &role.DeleteI{
+					Obj: []*role.DeleteI_Obj{
+						{
+							Metadata: met,
+						},
+					},
+				}
+
+				_, err = tc.cli.Role().Delete(context.Background(), i)
+			} else {
+				i := &role.UpdateI{
+					Obj: []*role.UpdateI_Obj{
+						{
+							Metadata: met,
+							Jsnpatch: []*role.UpdateI_Obj_Jsnpatch{
+								{
+									Ope: "replace",
+									Pat: "/obj/metadata/role.venturemark.co~1kind",
+									Val: to.StringP(tc.kin),
+								},
+							},
+						},
+					},
+				}
+
+				_, err = tc.cli.Role().Update(context.Background(), i)
+			}
+
+			if status.Code(err) != tc.code {
+				t.Fatalf("expected %s got %s", tc.code, status.Code(err))
+			}
+
+			// Changing timeline roles must never affect venture roles.
+			{
+				i := &role.SearchI{
+					Obj: []*role.SearchI_Obj{
+						{
+							Metadata: map[string]string{
+								"resource.venturemark.co/kind": "venture",
+								"venture.venturemark.co/id":    vei,
+							},
+						},
+					},
+				}
+
+				o, err := cl1.Role().Search(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				for _, x := range o.Obj {
+					if x.Metadata["role.venturemark.co/id"] == rv2 && x.Metadata["role.venturemark.co/kind"] != "member" {
+						t.Fatal("venture role must not change")
+					}
+				}
+			}
+		})
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "timeline",
+						"timeline.venturemark.co/id":   tii,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var kin string
+		for _, x := range o.Obj {
+			if x.Metadata["role.venturemark.co/id"] == rt2 {
+				kin = x.Metadata["role.venturemark.co/kind"]
+			}
+		}
+
+		if kin != "member" {
+			t.Fatal("kind must be member")
+		}
+	}
+
+	{
+		i := &role.DeleteI{
+			Obj: []*role.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "timeline",
+						"role.venturemark.co/id":       rt2,
+						"timeline.venturemark.co/id":   tii,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Role().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,1339 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/venturemark/apigengo/pkg/pbf/message"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/venturemark/apigengo/pkg/pbf/update"
+	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/to"
+)
+
+// Test_Search_001 ensures that hundreds of text updates per timeline are
+// returned newest first, without leaking updates across timelines.
+func Test_Search_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tis []string
+	{
+		for _, n := range []string{"Marketing Campaign", "Internal Project"} {
+			i := &timeline.CreateI{
+				Obj: []*timeline.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"venture.venturemark.co/id": vei,
+						},
+						Property: &timeline.CreateI_Obj_Property{
+							Name: n,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Timeline().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+
+			tis = append(tis, s)
+		}
+	}
+
+	var num int
+	{
+		num = 200
+	}
+
+	ups := map[string][]string{}
+	{
+		for j := 0; j < num; j++ {
+			for _, tii := range tis {
+				i := &texupd.CreateI{
+					Obj: []*texupd.CreateI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": tii,
+								"venture.venturemark.co/id":  vei,
+							},
+							Property: &texupd.CreateI_Obj_Property{
+								Text: fmt.Sprintf("Lorem ipsum %d", j),
+							},
+						},
+					},
+				}
+
+				o, err := cli.TexUpd().Create(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+				if !ok {
+					t.Fatal("id must not be empty")
+				}
+
+				ups[tii] = append(ups[tii], s)
+			}
+		}
+	}
+
+	{
+		for _, tii := range tis {
+			i := &update.SearchI{
+				Obj: []*update.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Update().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != num {
+				t.Fatalf("there must be %d updates", num)
+			}
+
+			for j, x := range o.Obj {
+				k := num - 1 - j
+
+				s, ok := x.Metadata["update.venturemark.co/id"]
+				if !ok {
+					t.Fatal("id must not be empty")
+				}
+				if s != ups[tii][k] {
+					t.Fatalf("update %d must be the one created at position %d", j, k)
+				}
+				if x.Metadata["timeline.venturemark.co/id"] != tii {
+					t.Fatal("update must belong to the searched timeline")
+				}
+				if x.Property.Text != fmt.Sprintf("Lorem ipsum %d", k) {
+					t.Fatalf("text must be Lorem ipsum %d", k)
+				}
+			}
+		}
+	}
+}
+
+// Test_Search_002 ensures that hundreds of messages per update are returned
+// newest first, without leaking messages across updates.
+func Test_Search_002(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var ups []string
+	{
+		for j := 0; j < 2; j++ {
+			i := &texupd.CreateI{
+				Obj: []*texupd.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &texupd.CreateI_Obj_Property{
+							Text: fmt.Sprintf("Lorem ipsum %d", j),
+						},
+					},
+				},
+			}
+
+			o, err := cli.TexUpd().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+
+			ups = append(ups, s)
+		}
+	}
+
+	var num int
+	{
+		num = 200
+	}
+
+	mes := map[string][]string{}
+	{
+		for j := 0; j < num; j++ {
+			for _, upi := range ups {
+				i := &message.CreateI{
+					Obj: []*message.CreateI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": tii,
+								"update.venturemark.co/id":   upi,
+								"venture.venturemark.co/id":  vei,
+							},
+							Property: &message.CreateI_Obj_Property{
+								Text: fmt.Sprintf("Lorem ipsum %d", j),
+							},
+						},
+					},
+				}
+
+				o, err := cli.Message().Create(context.Background(), i)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+				if !ok {
+					t.Fatal("id must not be empty")
+				}
+
+				mes[upi] = append(mes[upi], s)
+			}
+		}
+	}
+
+	{
+		for _, upi := range ups {
+			i := &message.SearchI{
+				Obj: []*message.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   upi,
+							"venture.venturemark.co/id":  vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Message().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != num {
+				t.Fatalf("there must be %d messages", num)
+			}
+
+			for j, x := range o.Obj {
+				k := num - 1 - j
+
+				s, ok := x.Metadata["message.venturemark.co/id"]
+				if !ok {
+					t.Fatal("id must not be empty")
+				}
+				if s != mes[upi][k] {
+					t.Fatalf("message %d must be the one created at position %d", j, k)
+				}
+				if x.Property.Text != fmt.Sprintf("Lorem ipsum %d", k) {
+					t.Fatalf("text must be Lorem ipsum %d", k)
+				}
+			}
+		}
+	}
+}
+
+// Test_Search_003 ensures that text updates created concurrently, and thus
+// likely sharing timestamps, are returned in a stable order across repeated
+// searches.
+func Test_Search_003(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var num int
+	{
+		num = 100
+	}
+
+	{
+		var w sync.WaitGroup
+		e := make(chan error, num)
+
+		for j := 0; j < num; j++ {
+			w.Add(1)
+			go func(j int) {
+				defer w.Done()
+
+				i := &texupd.CreateI{
+					Obj: []*texupd.CreateI_Obj{
+						{
+							Metadata: map[string]string{
+								"timeline.venturemark.co/id": tii,
+								"venture.venturemark.co/id":  vei,
+							},
+							Property: &texupd.CreateI_Obj_Property{
+								Text: fmt.Sprintf("Lorem ipsum %d", j),
+							},
+						},
+					},
+				}
+
+				_, err := cli.TexUpd().Create(context.Background(), i)
+				if err != nil {
+					e <- err
+				}
+			}(j)
+		}
+
+		w.Wait()
+		close(e)
+
+		for err := range e {
+			t.Fatal(err)
+		}
+	}
+
+	var fir []string
+	{
+		for j := 0; j < 3; j++ {
+			i := &update.SearchI{
+				Obj: []*update.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Update().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != num {
+				t.Fatalf("there must be %d updates", num)
+			}
+
+			var ids []string
+			see := map[string]bool{}
+			for _, x := range o.Obj {
+				s, ok := x.Metadata["update.venturemark.co/id"]
+				if !ok {
+					t.Fatal("id must not be empty")
+				}
+				if see[s] {
+					t.Fatal("id must be unique")
+				}
+
+				see[s] = true
+				ids = append(ids, s)
+			}
+
+			if fir == nil {
+				fir = ids
+				continue
+			}
+
+			for k := range ids {
+				if ids[k] != fir[k] {
+					t.Fatal("order must be stable across searches")
+				}
+			}
+		}
+	}
+}
+
+// Test_Search_004 ensures that paginating text updates via chunking yields
+// the same result as an unpaginated search, without gaps or duplicates.
+func Test_Search_004(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var num int
+	var per int
+	{
+		num = 110
+		per = 25
+	}
+
+	{
+		for j := 0; j < num; j++ {
+			i := &texupd.CreateI{
+				Obj: []*texupd.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &texupd.CreateI_Obj_Property{
+							Text: fmt.Sprintf("Lorem ipsum %d", j),
+						},
+					},
+				},
+			}
+
+			_, err := cli.TexUpd().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var all []string
+	{
+		i := &update.SearchI{
+			Obj: []*update.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Update().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != num {
+			t.Fatalf("there must be %d updates", num)
+		}
+
+		for _, x := range o.Obj {
+			all = append(all, x.Metadata["update.venturemark.co/id"])
+		}
+	}
+
+	var pag []string
+	{
+		var poi string
+
+		for {
+			i := &update.SearchI{
+				Api: &update.SearchI_API{
+					Chunking: &update.SearchI_API_Chunking{
+						Perpage: fmt.Sprintf("%d", per),
+						Pointer: poi,
+					},
+				},
+				Obj: []*update.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Update().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) > per {
+				t.Fatalf("page must not contain more than %d updates", per)
+			}
+
+			for _, x := range o.Obj {
+				pag = append(pag, x.Metadata["update.venturemark.co/id"])
+			}
+
+			if len(pag) > num {
+				t.Fatal("pages must not contain more updates than exist")
+			}
+
+			if len(o.Obj) < per || o.Api == nil || o.Api.Chunking == nil || o.Api.Chunking.Pointer == "" {
+				break
+			}
+			if o.Api.Chunking.Pointer == poi {
+				t.Fatal("pointer must advance across pages")
+			}
+
+			poi = o.Api.Chunking.Pointer
+		}
+	}
+
+	{
+		if len(pag) != len(all) {
+			t.Fatalf("pages must contain %d updates", len(all))
+		}
+
+		for j := range all {
+			if pag[j] != all[j] {
+				t.Fatal("pages must preserve the order of the unpaginated search")
+			}
+		}
+	}
+}
+
+// Test_Search_005 ensures that paginating messages via chunking yields the
+// same result as an unpaginated search, without gaps or duplicates.
+func Test_Search_005(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
+	var num int
+	var per int
+	{
+		num = 110
+		per = 25
+	}
+
+	{
+		for j := 0; j < num; j++ {
+			i := &message.CreateI{
+				Obj: []*message.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   upi,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &message.CreateI_Obj_Property{
+							Text: fmt.Sprintf("Lorem ipsum %d", j),
+						},
+					},
+				},
+			}
+
+			_, err := cli.Message().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var all []string
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != num {
+			t.Fatalf("there must be %d messages", num)
+		}
+
+		for _, x := range o.Obj {
+			all = append(all, x.Metadata["message.venturemark.co/id"])
+		}
+	}
+
+	var pag []string
+	{
+		var poi string
+
+		for {
+			i := &message.SearchI{
+				Api: &message.SearchI_API{
+					Chunking: &message.SearchI_API_Chunking{
+						Perpage: fmt.Sprintf("%d", per),
+						Pointer: poi,
+					},
+				},
+				Obj: []*message.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   upi,
+							"venture.venturemark.co/id":  vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Message().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) > per {
+				t.Fatalf("page must not contain more than %d messages", per)
+			}
+
+			for _, x := range o.Obj {
+				pag = append(pag, x.Metadata["message.venturemark.co/id"])
+			}
+
+			if len(pag) > num {
+				t.Fatal("pages must not contain more messages than exist")
+			}
+
+			if len(o.Obj) < per || o.Api == nil || o.Api.Chunking == nil || o.Api.Chunking.Pointer == "" {
+				break
+			}
+			if o.Api.Chunking.Pointer == poi {
+				t.Fatal("pointer must advance across pages")
+			}
+
+			poi = o.Api.Chunking.Pointer
+		}
+	}
+
+	{
+		if len(pag) != len(all) {
+			t.Fatalf("pages must contain %d messages", len(all))
+		}
+
+		for j := range all {
+			if pag[j] != all[j] {
+				t.Fatal("pages must preserve the order of the unpaginated search")
+			}
+		}
+	}
+}
+
+// Test_Search_006 ensures that searches can be filtered by timeline status, so
+// that timelines, updates and messages of archived timelines are separated
+// from those of active timelines.
+func Test_Search_006(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	var ti1 string
+	var ti2 string
+	{
+		for _, n := range []string{"Marketing Campaign", "Internal Project"} {
+			i := &timeline.CreateI{
+				Obj: []*timeline.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"venture.venturemark.co/id": vei,
+						},
+						Property: &timeline.CreateI_Obj_Property{
+							Name: n,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Timeline().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+
+			if ti1 == "" {
+				ti1 = s
+			} else {
+				ti2 = s
+			}
+		}
+	}
+
+	ups := map[string]string{}
+	{
+		for _, tii := range []string{ti1, ti2} {
+			i := &texupd.CreateI{
+				Obj: []*texupd.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &texupd.CreateI_Obj_Property{
+							Text: "Lorem ipsum",
+						},
+					},
+				},
+			}
+
+			o, err := cli.TexUpd().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+			if !ok {
+				t.Fatal("id must not be empty")
+			}
+
+			ups[tii] = s
+		}
+	}
+
+	{
+		for _, tii := range []string{ti1, ti2} {
+			i := &message.CreateI{
+				Obj: []*message.CreateI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id": tii,
+							"update.venturemark.co/id":   ups[tii],
+							"venture.venturemark.co/id":  vei,
+						},
+						Property: &message.CreateI_Obj_Property{
+							Text: "Lorem ipsum",
+						},
+					},
+				},
+			}
+
+			_, err := cli.Message().Create(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	{
+		i := &timeline.UpdateI{
+			Obj: []*timeline.UpdateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": ti2,
+						"venture.venturemark.co/id":  vei,
+					},
+					Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+						{
+							Ope: "replace",
+							Pat: "/obj/property/stat",
+							Val: to.StringP("archived"),
+						},
+					},
+				},
+			},
+		}
+
+		_, err := cli.Timeline().Update(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sta := map[string]string{
+		"active":   ti1,
+		"archived": ti2,
+	}
+
+	{
+		for s, tii := range sta {
+			i := &timeline.SearchI{
+				Obj: []*timeline.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/status": s,
+							"venture.venturemark.co/id":      vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Timeline().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != 1 {
+				t.Fatalf("there must be one %s timeline", s)
+			}
+			if o.Obj[0].Metadata["timeline.venturemark.co/id"] != tii {
+				t.Fatal("id must match across actions")
+			}
+			if o.Obj[0].Property.Stat != s {
+				t.Fatalf("stat must be %s", s)
+			}
+		}
+	}
+
+	{
+		for s, tii := range sta {
+			i := &update.SearchI{
+				Obj: []*update.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/status": s,
+							"venture.venturemark.co/id":      vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Update().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != 1 {
+				t.Fatalf("there must be one update of the %s timeline", s)
+			}
+			if o.Obj[0].Metadata["update.venturemark.co/id"] != ups[tii] {
+				t.Fatal("id must match across actions")
+			}
+		}
+	}
+
+	{
+		for s, tii := range sta {
+			i := &message.SearchI{
+				Obj: []*message.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id":     tii,
+							"timeline.venturemark.co/status": s,
+							"update.venturemark.co/id":       ups[tii],
+							"venture.venturemark.co/id":      vei,
+						},
+					},
+				},
+			}
+
+			o, err := cli.Message().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != 1 {
+				t.Fatalf("there must be one message of the %s timeline", s)
+			}
+		}
+	}
+
+	{
+		for s, tii := range sta {
+			var o string
+			if s == "active" {
+				o = "archived"
+			} else {
+				o = "active"
+			}
+
+			i := &message.SearchI{
+				Obj: []*message.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"timeline.venturemark.co/id":     tii,
+							"timeline.venturemark.co/status": o,
+							"update.venturemark.co/id":       ups[tii],
+							"venture.venturemark.co/id":      vei,
+						},
+					},
+				},
+			}
+
+			r, err := cli.Message().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(r.Obj) != 0 {
+				t.Fatalf("there must be zero messages of the %s timeline when filtering for %s", s, o)
+			}
+		}
+	}
+}
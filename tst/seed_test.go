@@ -0,0 +1,205 @@
+//go:build conformance
+// +build conformance
+
+package tst
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
+	"github.com/xh3b4sd/budget"
+	"github.com/xh3b4sd/tracer"
+
+	"github.com/venturemark/cfm/pkg/client"
+	"github.com/venturemark/cfm/pkg/oauth"
+	"github.com/venturemark/cfm/pkg/seed"
+)
+
+// Test_Seed_001 ensures that seeded data can be found using the credentials
+// of the manifest, that tearing it down leaves the storage empty, and that
+// seeding again with the same seed recreates the same content.
+func Test_Seed_001(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var see *seed.Seed
+	{
+		c := seed.Config{
+			Invites:   1,
+			Messages:  2,
+			Roles:     1,
+			Seed:      42,
+			Timelines: 2,
+			Updates:   2,
+			Users:     3,
+			Ventures:  1,
+		}
+
+		see, err = seed.New(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ma1 *seed.Manifest
+	{
+		ma1, err = see.Create(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ma1.Users) != 3 {
+			t.Fatal("there must be three users")
+		}
+	}
+
+	for _, u := range ma1.Users {
+		if len(u.Ventures) != 1 {
+			t.Fatal("there must be one venture per user")
+		}
+
+		for _, v := range u.Ventures {
+			if len(v.Roles) != 1 {
+				t.Fatal("there must be one member role per venture")
+			}
+
+			own := newClient(t, oauth.NewInsecure(u.Mail))
+
+			i := &timeline.SearchI{
+				Obj: []*timeline.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"venture.venturemark.co/id": v.ID,
+						},
+					},
+				},
+			}
+
+			o, err := own.Timeline().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != len(v.Timelines) {
+				t.Fatalf("there must be %d timelines", len(v.Timelines))
+			}
+		}
+	}
+
+	{
+		err = see.Delete(context.Background(), ma1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		o := func() error {
+			emp, err := empty(cli)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !emp {
+				return tracer.Mask(fmt.Errorf("storage must be empty"))
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ma2 *seed.Manifest
+	{
+		ma2, err = see.Create(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		err = see.Delete(context.Background(), ma2)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		if seedContent(t, ma1) != seedContent(t, ma2) {
+			t.Fatal("content must match across seeds")
+		}
+	}
+}
+
+// seedContent returns the JSON representation of man without any of the IDs
+// assigned by the apiserver. Note that the IDs of man get reset.
+func seedContent(t *testing.T, man *seed.Manifest) string {
+	for i, u := range man.Users {
+		man.Users[i].ID = ""
+
+		for j, v := range u.Ventures {
+			v.ID = ""
+
+			for k := range v.Invites {
+				v.Invites[k].ID = ""
+			}
+
+			for k := range v.Roles {
+				v.Roles[k].ID = ""
+				v.Roles[k].Subject = ""
+			}
+
+			for k, l := range v.Timelines {
+				v.Timelines[k].ID = ""
+
+				for m, d := range l.Updates {
+					l.Updates[m].ID = ""
+
+					for n := range d.Messages {
+						d.Messages[n].ID = ""
+						d.Messages[n].Reid = ""
+					}
+				}
+			}
+
+			man.Users[i].Ventures[j] = v
+		}
+	}
+
+	byt, err := json.Marshal(man)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(byt)
+}
@@ -1,3 +1,4 @@
+//go:build conformance
 // +build conformance
 
 package tst
@@ -42,19 +43,12 @@ func Test_TexUpd_001(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -577,7 +571,7 @@ func Test_TexUpd_001(t *testing.T) {
 
 	{
 		o := func() error {
-			emp, err := cli.Redigo().Empty()
+			emp, err := empty(cli)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -603,19 +597,12 @@ func Test_TexUpd_002(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -660,19 +647,12 @@ func Test_TexUpd_003(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -1285,19 +1265,12 @@ func Test_TexUpd_004(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -1,3 +1,4 @@
+//go:build conformance
 // +build conformance
 
 package tst
@@ -5,6 +6,7 @@ package tst
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -17,6 +19,8 @@ import (
 	"github.com/venturemark/apigengo/pkg/pbf/venture"
 	"github.com/xh3b4sd/budget"
 	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/venturemark/cfm/pkg/client"
 	"github.com/venturemark/cfm/pkg/oauth"
@@ -50,40 +54,22 @@ func Test_Timeline_001(t *testing.T) {
 
 	var cl1 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr1,
-		}
-
-		cl1, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl1 = newClient(t, cr1)
 
-		err = cl1.Redigo().Purge()
+		err = purge(cl1)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl1.Grpc().Close()
 	}
 
 	var cl2 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr2,
-		}
+		cl2 = newClient(t, cr2)
 
-		cl2, err = client.New(c)
+		err = purge(cl2)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cl2.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cl2.Grpc().Close()
 	}
 
 	{
@@ -651,7 +637,7 @@ func Test_Timeline_001(t *testing.T) {
 
 	{
 		o := func() error {
-			emp, err := cl1.Redigo().Empty()
+			emp, err := empty(cl1)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -676,19 +662,12 @@ func Test_Timeline_002(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -855,19 +834,12 @@ func Test_Timeline_003(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -1077,19 +1049,12 @@ func Test_Timeline_004(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -1262,40 +1227,22 @@ func Test_Timeline_005(t *testing.T) {
 
 	var cl1 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr1,
-		}
+		cl1 = newClient(t, cr1)
 
-		cl1, err = client.New(c)
+		err = purge(cl1)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cl1.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cl1.Grpc().Close()
 	}
 
 	var cl2 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr2,
-		}
-
-		cl2, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl2 = newClient(t, cr2)
 
-		err = cl2.Redigo().Purge()
+		err = purge(cl2)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl2.Grpc().Close()
 	}
 
 	{
@@ -1435,19 +1382,12 @@ func Test_Timeline_006(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
-
-		cli, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cli = newClient(t, nil)
 
-		err = cli.Redigo().Purge()
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -2032,19 +1972,12 @@ func Test_Timeline_007(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -2065,3 +1998,370 @@ func Test_Timeline_007(t *testing.T) {
 		}
 	}
 }
+
+// Test_Timeline_008 ensures that the timeline state machine behaves as
+// specified. Timelines know the states active and archived. Every transition
+// between known states is allowed, including transitions into the current
+// state. Transitions into unknown states are rejected and leave the timeline
+// untouched. Archived timelines reject the creation of text updates and
+// messages. Only archived timelines can be deleted.
+func Test_Timeline_008(t *testing.T) {
+	var err error
+
+	var cli *client.Client
+	{
+		cli = newClient(t, nil)
+
+		err = purge(cli)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		_, err := cli.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cli.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	kno := []string{
+		"active",
+		"archived",
+	}
+
+	unk := []string{
+		"",
+		" archived",
+		"Archived",
+		"ACTIVE",
+		"deleted",
+		"paused",
+	}
+
+	var sta []string
+	{
+		sta = append(sta, kno...)
+		sta = append(sta, unk...)
+	}
+
+	var j int
+	for _, fro := range kno {
+		for _, des := range sta {
+			// Every transition gets its own timeline. Timeline names must be
+			// unique within a venture.
+			nam := fmt.Sprintf("Timeline %d", j)
+
+			t.Run(strconv.Itoa(j), func(t *testing.T) {
+				tii, upi := timelineCreate(t, cli, vei, nam)
+
+				if fro != "active" {
+					err := timelinePatch(cli, vei, tii, fro)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				var exp string
+				{
+					exp = fro
+					for _, k := range kno {
+						if des == k {
+							exp = des
+						}
+					}
+				}
+
+				{
+					err := timelinePatch(cli, vei, tii, des)
+					if exp == des && err != nil {
+						t.Fatalf("transition from %q to %q must be allowed: %s", fro, des, err)
+					}
+					if exp != des && status.Code(tracer.Cause(err)) != codes.InvalidArgument {
+						t.Fatalf("expected %s got %s", codes.InvalidArgument, status.Code(tracer.Cause(err)))
+					}
+				}
+
+				{
+					s := timelineStat(t, cli, vei, tii)
+					if s != exp {
+						t.Fatalf("stat must be %q got %q", exp, s)
+					}
+				}
+
+				{
+					i := &texupd.CreateI{
+						Obj: []*texupd.CreateI_Obj{
+							{
+								Metadata: map[string]string{
+									"timeline.venturemark.co/id": tii,
+									"venture.venturemark.co/id":  vei,
+								},
+								Property: &texupd.CreateI_Obj_Property{
+									Head: "title",
+									Text: "Lorem ipsum",
+								},
+							},
+						},
+					}
+
+					_, err := cli.TexUpd().Create(context.Background(), i)
+					if exp == "active" && err != nil {
+						t.Fatalf("text update must be created on active timeline: %s", err)
+					}
+					if exp == "archived" && status.Code(tracer.Cause(err)) != codes.FailedPrecondition {
+						t.Fatalf("expected %s got %s", codes.FailedPrecondition, status.Code(tracer.Cause(err)))
+					}
+				}
+
+				{
+					i := &message.CreateI{
+						Obj: []*message.CreateI_Obj{
+							{
+								Metadata: map[string]string{
+									"timeline.venturemark.co/id": tii,
+									"update.venturemark.co/id":   upi,
+									"venture.venturemark.co/id":  vei,
+								},
+								Property: &message.CreateI_Obj_Property{
+									Text: "Lorem ipsum",
+								},
+							},
+						},
+					}
+
+					_, err := cli.Message().Create(context.Background(), i)
+					if exp == "active" && err != nil {
+						t.Fatalf("message must be created on active timeline: %s", err)
+					}
+					if exp == "archived" && status.Code(tracer.Cause(err)) != codes.FailedPrecondition {
+						t.Fatalf("expected %s got %s", codes.FailedPrecondition, status.Code(tracer.Cause(err)))
+					}
+				}
+
+				{
+					i := &timeline.DeleteI{
+						Obj: []*timeline.DeleteI_Obj{
+							{
+								Metadata: map[string]string{
+									"timeline.venturemark.co/id": tii,
+									"venture.venturemark.co/id":  vei,
+								},
+							},
+						},
+					}
+
+					_, err := cli.Timeline().Delete(context.Background(), i)
+					if exp == "archived" && err != nil {
+						t.Fatalf("archived timeline must be deleted: %s", err)
+					}
+					if exp == "active" && status.Code(tracer.Cause(err)) != codes.FailedPrecondition {
+						t.Fatalf("expected %s got %s", codes.FailedPrecondition, status.Code(tracer.Cause(err)))
+					}
+				}
+
+				// Timelines which could not be deleted above are archived and
+				// deleted in order to leave the venture clean for the next case.
+				if exp == "active" {
+					err := timelinePatch(cli, vei, tii, "archived")
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					i := &timeline.DeleteI{
+						Obj: []*timeline.DeleteI_Obj{
+							{
+								Metadata: map[string]string{
+									"timeline.venturemark.co/id": tii,
+									"venture.venturemark.co/id":  vei,
+								},
+							},
+						},
+					}
+
+					_, err = cli.Timeline().Delete(context.Background(), i)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+
+			j++
+		}
+	}
+
+	{
+		i := &venture.DeleteI{
+			Obj: []*venture.DeleteI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		_, err := cli.Venture().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// timelineCreate creates an active timeline with the given name within the
+// venture vei, together with one text update. It returns the timeline ID and
+// the update ID.
+func timelineCreate(t *testing.T, cli *client.Client, vei string, nam string) (string, string) {
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: nam,
+					},
+				},
+			},
+		}
+
+		o, err := cli.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Head: "title",
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cli.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
+	return tii, upi
+}
+
+// timelinePatch replaces the state of the timeline tii with sta.
+func timelinePatch(cli *client.Client, vei string, tii string, sta string) error {
+	i := &timeline.UpdateI{
+		Obj: []*timeline.UpdateI_Obj{
+			{
+				Metadata: map[string]string{
+					"timeline.venturemark.co/id": tii,
+					"venture.venturemark.co/id":  vei,
+				},
+				Jsnpatch: []*timeline.UpdateI_Obj_Jsnpatch{
+					{
+						Ope: "replace",
+						Pat: "/obj/property/stat",
+						Val: to.StringP(sta),
+					},
+				},
+			},
+		},
+	}
+
+	_, err := cli.Timeline().Update(context.Background(), i)
+	if err != nil {
+		return tracer.Mask(err)
+	}
+
+	return nil
+}
+
+// timelineStat returns the state of the timeline tii as returned by the search
+// API, which lists all timelines of the venture vei.
+func timelineStat(t *testing.T, cli *client.Client, vei string, tii string) string {
+	i := &timeline.SearchI{
+		Obj: []*timeline.SearchI_Obj{
+			{
+				Metadata: map[string]string{
+					"venture.venturemark.co/id": vei,
+				},
+			},
+		},
+	}
+
+	o, err := cli.Timeline().Search(context.Background(), i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, x := range o.Obj {
+		if x.Metadata["timeline.venturemark.co/id"] == tii {
+			return x.Property.Stat
+		}
+	}
+
+	t.Fatal("timeline must exist")
+
+	return ""
+}
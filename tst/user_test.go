@@ -1,3 +1,4 @@
+//go:build conformance
 // +build conformance
 
 package tst
@@ -8,10 +9,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/venturemark/apigengo/pkg/pbf/invite"
+	"github.com/venturemark/apigengo/pkg/pbf/message"
 	"github.com/venturemark/apigengo/pkg/pbf/role"
+	"github.com/venturemark/apigengo/pkg/pbf/texupd"
+	"github.com/venturemark/apigengo/pkg/pbf/timeline"
 	"github.com/venturemark/apigengo/pkg/pbf/user"
+	"github.com/venturemark/apigengo/pkg/pbf/venture"
 	"github.com/xh3b4sd/budget"
 	"github.com/xh3b4sd/tracer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/venturemark/cfm/pkg/client"
 	"github.com/venturemark/cfm/pkg/oauth"
@@ -45,40 +53,22 @@ func Test_User_001(t *testing.T) {
 
 	var cl1 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr1,
-		}
-
-		cl1, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl1 = newClient(t, cr1)
 
-		err = cl1.Redigo().Purge()
+		err = purge(cl1)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl1.Grpc().Close()
 	}
 
 	var cl2 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr2,
-		}
-
-		cl2, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl2 = newClient(t, cr2)
 
-		err = cl2.Redigo().Purge()
+		err = purge(cl2)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl2.Grpc().Close()
 	}
 
 	var us1 string
@@ -794,19 +784,12 @@ func Test_User_002(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
-
-		cli, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cli = newClient(t, nil)
 
-		err = cli.Redigo().Purge()
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -834,19 +817,12 @@ func Test_User_003(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
-
-		cli, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cli = newClient(t, nil)
 
-		err = cli.Redigo().Purge()
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -890,3 +866,920 @@ func Test_User_003(t *testing.T) {
 		}
 	}
 }
+
+// Test_User_004 ensures that users being the sole owner of a venture cannot
+// delete their account. The venture's ownership and the messages authored by
+// the owner must remain unchanged.
+func Test_User_004(t *testing.T) {
+	var err error
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	var us1 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		us1 = s
+	}
+
+	var us2 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		us2 = s
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    us2,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Head: "title",
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
+	var mei string
+	{
+		i := &message.CreateI{
+			Obj: []*message.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Message().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		mei = s
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		_, err := cl1.User().Delete(context.Background(), i)
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("expected %s got %s", codes.FailedPrecondition, status.Code(err))
+		}
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 2 {
+			t.Fatal("there must be two roles")
+		}
+
+		kin := map[string]string{}
+		for _, x := range o.Obj {
+			kin[x.Metadata["subject.venturemark.co/id"]] = x.Metadata["role.venturemark.co/kind"]
+		}
+
+		if kin[us1] != "owner" {
+			t.Fatal("user one must be owner")
+		}
+		if kin[us2] != "member" {
+			t.Fatal("user two must be member")
+		}
+	}
+
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one message")
+		}
+		if o.Obj[0].Metadata["message.venturemark.co/id"] != mei {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Metadata["user.venturemark.co/id"] != us1 {
+			t.Fatal("author must match across actions")
+		}
+	}
+}
+
+// Test_User_005 ensures that members can delete their account. Their roles
+// must be removed, while the venture remains owned by its owner and the
+// messages authored by the deleted member remain attributed to them.
+func Test_User_005(t *testing.T) {
+	var err error
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	var us1 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		us1 = s
+	}
+
+	var us2 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		us2 = s
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    us2,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tii string
+	{
+		i := &timeline.CreateI{
+			Obj: []*timeline.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &timeline.CreateI_Obj_Property{
+						Name: "Marketing Campaign",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Timeline().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["timeline.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		tii = s
+	}
+
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "timeline",
+						"role.venturemark.co/kind":     "member",
+						"subject.venturemark.co/id":    us2,
+						"timeline.venturemark.co/id":   tii,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var upi string
+	{
+		i := &texupd.CreateI{
+			Obj: []*texupd.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &texupd.CreateI_Obj_Property{
+						Head: "title",
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.TexUpd().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["update.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		upi = s
+	}
+
+	var mei string
+	{
+		i := &message.CreateI{
+			Obj: []*message.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+					Property: &message.CreateI_Obj_Property{
+						Text: "Lorem ipsum",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.Message().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["message.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		mei = s
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		o, err := cl2.User().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+	}
+
+	{
+		o := func() error {
+			i := &role.SearchI{
+				Obj: []*role.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"resource.venturemark.co/kind": "venture",
+							"venture.venturemark.co/id":    vei,
+						},
+					},
+				},
+			}
+
+			o, err := cl1.Role().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != 1 {
+				return tracer.Mask(fmt.Errorf("there must be one role"))
+			}
+			if o.Obj[0].Metadata["subject.venturemark.co/id"] != us1 {
+				t.Fatal("user one must remain")
+			}
+			if o.Obj[0].Metadata["role.venturemark.co/kind"] != "owner" {
+				t.Fatal("user one must be owner")
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		o := func() error {
+			i := &role.SearchI{
+				Obj: []*role.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"resource.venturemark.co/kind": "timeline",
+							"timeline.venturemark.co/id":   tii,
+							"venture.venturemark.co/id":    vei,
+						},
+					},
+				},
+			}
+
+			o, err := cl1.Role().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, x := range o.Obj {
+				if x.Metadata["subject.venturemark.co/id"] == us2 {
+					return tracer.Mask(fmt.Errorf("user two must not have timeline roles"))
+				}
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &message.SearchI{
+			Obj: []*message.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"timeline.venturemark.co/id": tii,
+						"update.venturemark.co/id":   upi,
+						"venture.venturemark.co/id":  vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Message().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one message")
+		}
+		if o.Obj[0].Metadata["message.venturemark.co/id"] != mei {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Metadata["user.venturemark.co/id"] != us2 {
+			t.Fatal("author must match across actions")
+		}
+	}
+}
+
+// Test_User_006 ensures that owners can delete their account while invites
+// they sent are still pending, as long as the venture has another owner. The
+// pending invites must remain valid for the venture.
+func Test_User_006(t *testing.T) {
+	var err error
+
+	var b budget.Interface
+	{
+		c := budget.ConstantConfig{
+			Budget:   9,
+			Duration: 5 * time.Second,
+		}
+
+		b, err = budget.NewConstant(c)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var cr1 *oauth.Insecure
+	var cr2 *oauth.Insecure
+	{
+		cr1 = oauth.NewInsecureOne()
+		cr2 = oauth.NewInsecureTwo()
+	}
+
+	var cl1 *client.Client
+	{
+		cl1 = newClient(t, cr1)
+
+		err = purge(cl1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl2 := newClient(t, cr2)
+
+	var us1 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "marcojelli",
+						Mail: "m@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		us1 = s
+	}
+
+	var us2 string
+	{
+		i := &user.CreateI{
+			Obj: []*user.CreateI_Obj{
+				{
+					Property: &user.CreateI_Obj_Property{
+						Name: "disreszi",
+						Mail: "d@example.com",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.User().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		us2 = s
+	}
+
+	var vei string
+	{
+		i := &venture.CreateI{
+			Obj: []*venture.CreateI_Obj{
+				{
+					Property: &venture.CreateI_Obj_Property{
+						Name: "IBM",
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Venture().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["venture.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		vei = s
+	}
+
+	{
+		i := &role.CreateI{
+			Obj: []*role.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"resource.venturemark.co/kind": "venture",
+						"role.venturemark.co/kind":     "owner",
+						"subject.venturemark.co/id":    us2,
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		_, err := cl1.Role().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ini string
+	{
+		i := &invite.CreateI{
+			Obj: []*invite.CreateI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+					Property: &invite.CreateI_Obj_Property{
+						Mail: "user3@site.net",
+					},
+				},
+			},
+		}
+
+		o, err := cl2.Invite().Create(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["invite.venturemark.co/id"]
+		if !ok {
+			t.Fatal("id must not be empty")
+		}
+
+		ini = s
+	}
+
+	{
+		i := &user.DeleteI{}
+
+		o, err := cl2.User().Delete(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s, ok := o.Obj[0].Metadata["user.venturemark.co/status"]
+		if !ok {
+			t.Fatal("status must not be empty")
+		}
+
+		if s != "deleted" {
+			t.Fatal("status must be deleted")
+		}
+	}
+
+	{
+		o := func() error {
+			i := &role.SearchI{
+				Obj: []*role.SearchI_Obj{
+					{
+						Metadata: map[string]string{
+							"resource.venturemark.co/kind": "venture",
+							"venture.venturemark.co/id":    vei,
+						},
+					},
+				},
+			}
+
+			o, err := cl1.Role().Search(context.Background(), i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(o.Obj) != 1 {
+				return tracer.Mask(fmt.Errorf("there must be one role"))
+			}
+			if o.Obj[0].Metadata["subject.venturemark.co/id"] != us1 {
+				t.Fatal("user one must remain")
+			}
+			if o.Obj[0].Metadata["role.venturemark.co/kind"] != "owner" {
+				t.Fatal("user one must be owner")
+			}
+
+			return nil
+		}
+
+		err = b.Execute(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		i := &role.SearchI{
+			Obj: []*role.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"invite.venturemark.co/id":     ini,
+						"resource.venturemark.co/kind": "invite",
+						"venture.venturemark.co/id":    vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Role().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, x := range o.Obj {
+			if x.Metadata["subject.venturemark.co/id"] == us2 {
+				t.Fatal("user two must not have invite roles")
+			}
+		}
+	}
+
+	{
+		i := &invite.SearchI{
+			Obj: []*invite.SearchI_Obj{
+				{
+					Metadata: map[string]string{
+						"venture.venturemark.co/id": vei,
+					},
+				},
+			},
+		}
+
+		o, err := cl1.Invite().Search(context.Background(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(o.Obj) != 1 {
+			t.Fatal("there must be one invite")
+		}
+		if o.Obj[0].Metadata["invite.venturemark.co/id"] != ini {
+			t.Fatal("id must match across actions")
+		}
+		if o.Obj[0].Property.Stat != "pending" {
+			t.Fatal("stat must be pending")
+		}
+	}
+}
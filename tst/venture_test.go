@@ -1,3 +1,4 @@
+//go:build conformance
 // +build conformance
 
 package tst
@@ -46,40 +47,22 @@ func Test_Venture_001(t *testing.T) {
 
 	var cl1 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr1,
-		}
-
-		cl1, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl1 = newClient(t, cr1)
 
-		err = cl1.Redigo().Purge()
+		err = purge(cl1)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl1.Grpc().Close()
 	}
 
 	var cl2 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr2,
-		}
+		cl2 = newClient(t, cr2)
 
-		cl2, err = client.New(c)
+		err = purge(cl2)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cl2.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cl2.Grpc().Close()
 	}
 
 	var us1 string
@@ -1007,7 +990,7 @@ func Test_Venture_001(t *testing.T) {
 
 	{
 		o := func() error {
-			emp, err := cl1.Redigo().Empty()
+			emp, err := empty(cl1)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -1033,19 +1016,12 @@ func Test_Venture_002(t *testing.T) {
 
 	var cli *client.Client
 	{
-		c := client.Config{}
+		cli = newClient(t, nil)
 
-		cli, err = client.New(c)
+		err = purge(cli)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cli.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cli.Grpc().Close()
 	}
 
 	{
@@ -1080,40 +1056,22 @@ func Test_Venture_003(t *testing.T) {
 
 	var cl1 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr1,
-		}
-
-		cl1, err = client.New(c)
-		if err != nil {
-			t.Fatal(err)
-		}
+		cl1 = newClient(t, cr1)
 
-		err = cl1.Redigo().Purge()
+		err = purge(cl1)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		defer cl1.Grpc().Close()
 	}
 
 	var cl2 *client.Client
 	{
-		c := client.Config{
-			Credentials: cr2,
-		}
+		cl2 = newClient(t, cr2)
 
-		cl2, err = client.New(c)
+		err = purge(cl2)
 		if err != nil {
 			t.Fatal(err)
 		}
-
-		err = cl2.Redigo().Purge()
-		if err != nil {
-			t.Fatal(err)
-		}
-
-		defer cl2.Grpc().Close()
 	}
 
 	var us1 string